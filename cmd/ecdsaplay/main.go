@@ -0,0 +1,216 @@
+// Command ecdsaplay generates key pairs, signs files and verifies signatures
+// using the ecdsaplay package:
+//
+//	ecdsaplay keygen -curve P256 -out key.pem -pubout pub.pem
+//	ecdsaplay sign -key key.pem -in file -out sig.der
+//	ecdsaplay verify -key pub.pem -sig sig.der -in file
+//
+// Keys are PKCS #8 and SubjectPublicKeyInfo PEM files as used by OpenSSL and
+// signatures are DER encoded. The file is hashed with SHA-256, SHA-384 or
+// SHA-512 matching the size of the curve
+package main
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"playgroundgo/ecdsaPlay"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "verify":
+		var valid bool
+		valid, err = verify(os.Args[2:])
+		if err == nil {
+			fmt.Println("Valid Signature: ", valid)
+			if !valid {
+				os.Exit(1)
+			}
+		}
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ecdsaplay keygen|sign|verify [flags]")
+	os.Exit(2)
+}
+
+// Generates a key pair on the named curve, writing the private key to -out
+// and, if given, the public key to -pubout
+func keygen(args []string) error {
+	var flags = flag.NewFlagSet("keygen", flag.ExitOnError)
+	var curveName = flags.String("curve", "P256", "curve: P224, P256, P384 or P521")
+	var out = flags.String("out", "key.pem", "private key PEM file to write")
+	var pubOut = flags.String("pubout", "", "public key PEM file to write")
+	flags.Parse(args)
+
+	return generateKey(*curveName, *out, *pubOut)
+}
+
+// Generates a key pair on the named curve, writing the private key to the
+// file out and, unless pubOut is empty, the public key to the file pubOut
+func generateKey(curveName, out, pubOut string) error {
+	curve, err := curveByName(curveName)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+	if err != nil {
+		return err
+	}
+
+	privatePEM, err := ecdsaplay.MarshalPKCS8PEM(key)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(out, privatePEM, 0600); err != nil {
+		return err
+	}
+
+	if pubOut == "" {
+		return nil
+	}
+	publicPEM, err := ecdsaplay.MarshalPublicKeyPEM(key.PublicKey())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pubOut, publicPEM, 0644)
+}
+
+// Signs the file -in with the private key -key, writing the DER encoded
+// signature to -out
+func sign(args []string) error {
+	var flags = flag.NewFlagSet("sign", flag.ExitOnError)
+	var keyFile = flags.String("key", "key.pem", "private key PEM file")
+	var in = flags.String("in", "", "file to sign")
+	var out = flags.String("out", "sig.der", "DER signature file to write")
+	flags.Parse(args)
+
+	return signFile(*keyFile, *in, *out)
+}
+
+// Signs the file in with the private key of the PEM file keyFile, writing
+// the DER encoded signature to the file out
+func signFile(keyFile, in, out string) error {
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	key, err := ecdsaplay.ParsePKCS8PEM(keyPEM)
+	if err != nil {
+		return err
+	}
+
+	messageHash, err := hashFile(in, key.Curve)
+	if err != nil {
+		return err
+	}
+
+	signature, err := ecdsaplay.SignAndEncode(key, messageHash)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, signature, 0644)
+}
+
+// Verifies the DER encoded signature -sig over the file -in against the
+// public key -key
+func verify(args []string) (bool, error) {
+	var flags = flag.NewFlagSet("verify", flag.ExitOnError)
+	var keyFile = flags.String("key", "pub.pem", "public key PEM file")
+	var sigFile = flags.String("sig", "sig.der", "DER signature file")
+	var in = flags.String("in", "", "file to verify")
+	flags.Parse(args)
+
+	return verifyFile(*keyFile, *sigFile, *in)
+}
+
+// Verifies the DER encoded signature of the file sigFile over the file in
+// against the public key of the PEM file keyFile
+func verifyFile(keyFile, sigFile, in string) (bool, error) {
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return false, err
+	}
+	pub, err := ecdsaplay.ParsePublicKeyPEM(keyPEM)
+	if err != nil {
+		return false, err
+	}
+
+	signature, err := os.ReadFile(sigFile)
+	if err != nil {
+		return false, err
+	}
+
+	messageHash, err := hashFile(in, pub.Curve)
+	if err != nil {
+		return false, err
+	}
+
+	return ecdsaplay.VerifyDER(signature, pub, messageHash)
+}
+
+// NIST curve by its name with or without the hyphen, e.g. P256 or P-256
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P224", "P-224":
+		return elliptic.P224(), nil
+	case "P256", "P-256":
+		return elliptic.P256(), nil
+	case "P384", "P-384":
+		return elliptic.P384(), nil
+	case "P521", "P-521":
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("Error: Unsupported curve %q", name)
+}
+
+// Hashes the file with SHA-256 for curves of up to 256 bits, SHA-384 for
+// P-384 and SHA-512 for P-521
+func hashFile(name string, curve elliptic.Curve) ([]byte, error) {
+	if name == "" {
+		return nil, errors.New("Error: No input file, -in is required")
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var h = sha256.New()
+	switch bitSize := curve.Params().BitSize; {
+	case bitSize > 384:
+		h = sha512.New()
+	case bitSize > 256:
+		h = sha512.New384()
+	}
+
+	if _, err = io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
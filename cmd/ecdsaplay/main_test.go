@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeygenSignVerify(t *testing.T) {
+	for _, curveName := range []string{"P224", "P256", "P-384", "P521"} {
+		var dir = t.TempDir()
+		var keyFile = filepath.Join(dir, "key.pem")
+		var pubFile = filepath.Join(dir, "pub.pem")
+		var sigFile = filepath.Join(dir, "sig.der")
+		var in = filepath.Join(dir, "message.txt")
+
+		if err := os.WriteFile(in, []byte("Take the red pill!"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := keygen([]string{"-curve", curveName, "-out", keyFile, "-pubout", pubFile}); err != nil {
+			t.Fatalf("%s: keygen: %v", curveName, err)
+		}
+		if err := sign([]string{"-key", keyFile, "-in", in, "-out", sigFile}); err != nil {
+			t.Fatalf("%s: sign: %v", curveName, err)
+		}
+
+		valid, err := verify([]string{"-key", pubFile, "-sig", sigFile, "-in", in})
+		if err != nil || !valid {
+			t.Fatalf("%s: verify = %v, %v; want true, nil", curveName, valid, err)
+		}
+
+		// The same signature over a different file must be rejected
+		if err = os.WriteFile(in, []byte("Take the green pill!"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		valid, err = verifyFile(pubFile, sigFile, in)
+		if err != nil || valid {
+			t.Fatalf("%s: verify of a changed file = %v, %v; want false, nil", curveName, valid, err)
+		}
+	}
+}
+
+func TestKeygenErrors(t *testing.T) {
+	var dir = t.TempDir()
+
+	if err := generateKey("P192", filepath.Join(dir, "key.pem"), ""); err == nil {
+		t.Error("generateKey with an unsupported curve succeeded")
+	}
+	if err := generateKey("P256", filepath.Join(dir, "missing", "key.pem"), ""); err == nil {
+		t.Error("generateKey into a missing directory succeeded")
+	}
+}
+
+func TestSignVerifyErrors(t *testing.T) {
+	var dir = t.TempDir()
+	var keyFile = filepath.Join(dir, "key.pem")
+	var pubFile = filepath.Join(dir, "pub.pem")
+	var sigFile = filepath.Join(dir, "sig.der")
+
+	if err := generateKey("P256", keyFile, pubFile); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := signFile(keyFile, "", sigFile); err == nil {
+		t.Error("signFile without an input file succeeded")
+	}
+	if err := signFile(pubFile, keyFile, sigFile); err == nil {
+		t.Error("signFile with a public key succeeded")
+	}
+	if _, err := verifyFile(pubFile, filepath.Join(dir, "missing.der"), keyFile); err == nil {
+		t.Error("verifyFile with a missing signature succeeded")
+	}
+}
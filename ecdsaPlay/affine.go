@@ -0,0 +1,106 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Point addition in affine coordinates following the chord rule: the line
+// through P1 and P2 has slope lambda = (y2 - y1)/(x2 - x1) and meets the curve
+// in a third point, whose reflection over the x-axis is the sum
+//
+//	x3 = lambda^2 - x1 - x2
+//	y3 = lambda(x1 - x3) - y1
+//
+// all mod P. As with crypto/elliptic the point at infinity is (0, 0); it is
+// the result of P + (-P), and the identity for any other point. P1 = P2 is
+// handed to PointDouble since the chord becomes a tangent
+func PointAdd(curve elliptic.Curve, x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	var p = curve.Params().P
+
+	if isInfinity(x1, y1) {
+		return new(big.Int).Set(x2), new(big.Int).Set(y2)
+	}
+	if isInfinity(x2, y2) {
+		return new(big.Int).Set(x1), new(big.Int).Set(y1)
+	}
+
+	if x1.Cmp(x2) == 0 {
+		// Either P2 = -P1, where the line is vertical, or P2 = P1
+		var sum = new(big.Int).Add(y1, y2)
+		if sum.Mod(sum, p).Sign() == 0 {
+			return new(big.Int), new(big.Int)
+		}
+		return PointDouble(curve, x1, y1)
+	}
+
+	// lambda = (y2 - y1)/(x2 - x1) mod P
+	var dx = new(big.Int).Sub(x2, x1)
+	dx.Mod(dx, p)
+	var lambda = new(big.Int).Sub(y2, y1)
+	lambda.Mul(lambda, inverse(dx, p))
+	lambda.Mod(lambda, p)
+
+	return chordPoint(p, lambda, x1, y1, x2)
+}
+
+// Point doubling in affine coordinates following the tangent rule: the
+// tangent at P has slope lambda = (3x^2 + a)/(2y), from implicit
+// differentiation of y^2 = x^3 + ax + b, and
+//
+//	x3 = lambda^2 - 2x
+//	y3 = lambda(x - x3) - y
+//
+// all mod P. A point with y = 0 has a vertical tangent and doubles to the
+// point at infinity (0, 0)
+func PointDouble(curve elliptic.Curve, x, y *big.Int) (x3, y3 *big.Int) {
+	var p = curve.Params().P
+
+	if isInfinity(x, y) || new(big.Int).Mod(y, p).Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	// lambda = (3x^2 + a)/(2y) mod P
+	var twoY = new(big.Int).Lsh(y, 1)
+	twoY.Mod(twoY, p)
+	var lambda = new(big.Int).Mul(x, x)
+	lambda.Mul(lambda, big.NewInt(3))
+	lambda.Add(lambda, curveA(curve))
+	lambda.Mul(lambda, inverse(twoY, p))
+	lambda.Mod(lambda, p)
+
+	return chordPoint(p, lambda, x, y, x)
+}
+
+// -(x, y) = (x, -y mod P), the reflection over the x-axis. The point at
+// infinity is its own negation
+func PointNegate(curve elliptic.Curve, x, y *big.Int) (x3, y3 *big.Int) {
+	if isInfinity(x, y) {
+		return new(big.Int), new(big.Int)
+	}
+
+	var p = curve.Params().P
+	y3 = new(big.Int).Neg(y)
+	y3.Mod(y3, p)
+	return new(big.Int).Set(x), y3
+}
+
+// x3 = lambda^2 - x1 - x2 and y3 = lambda(x1 - x3) - y1 mod p
+func chordPoint(p, lambda, x1, y1, x2 *big.Int) (x3, y3 *big.Int) {
+	x3 = new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, p)
+
+	y3 = new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, y1)
+	y3.Mod(y3, p)
+
+	return x3, y3
+}
+
+// Whether (x, y) is the point at infinity, represented as (0, 0)
+func isInfinity(x, y *big.Int) bool {
+	return x.Sign() == 0 && y.Sign() == 0
+}
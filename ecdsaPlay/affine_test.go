@@ -0,0 +1,78 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestAffineMatchesElliptic(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		for i := 0; i < 20; i++ {
+			var p1, p2 = generateKey(t, curve), generateKey(t, curve)
+			var negX, negY = PointNegate(curve, p1.PublicX, p1.PublicY)
+
+			var tests = []struct {
+				name           string
+				x1, y1, x2, y2 *big.Int
+			}{
+				{"P1 + P2", p1.PublicX, p1.PublicY, p2.PublicX, p2.PublicY},
+				{"P1 + P1", p1.PublicX, p1.PublicY, p1.PublicX, p1.PublicY},
+				{"P1 + -P1", p1.PublicX, p1.PublicY, negX, negY},
+				{"0 + P1", new(big.Int), new(big.Int), p1.PublicX, p1.PublicY},
+				{"P1 + 0", p1.PublicX, p1.PublicY, new(big.Int), new(big.Int)},
+			}
+
+			for _, test := range tests {
+				x, y := PointAdd(curve, test.x1, test.y1, test.x2, test.y2)
+				wantX, wantY := curve.Add(test.x1, test.y1, test.x2, test.y2)
+				if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+					t.Fatalf("%s %s: PointAdd = (%x, %x), want (%x, %x)", curve.Params().Name, test.name, x, y, wantX, wantY)
+				}
+			}
+
+			x, y := PointDouble(curve, p1.PublicX, p1.PublicY)
+			wantX, wantY := curve.Double(p1.PublicX, p1.PublicY)
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Fatalf("%s: PointDouble = (%x, %x), want (%x, %x)", curve.Params().Name, x, y, wantX, wantY)
+			}
+
+			// -P = (N-1)P, on the curve with the same x
+			wantX, wantY = curve.ScalarMult(p1.PublicX, p1.PublicY, new(big.Int).Sub(curve.Params().N, big.NewInt(1)).Bytes())
+			if negX.Cmp(wantX) != 0 || negY.Cmp(wantY) != 0 || !curve.IsOnCurve(negX, negY) {
+				t.Fatalf("%s: PointNegate = (%x, %x), want (%x, %x)", curve.Params().Name, negX, negY, wantX, wantY)
+			}
+		}
+
+		if x, y := PointNegate(curve, new(big.Int), new(big.Int)); !isInfinity(x, y) {
+			t.Errorf("%s: PointNegate of infinity = (%x, %x)", curve.Params().Name, x, y)
+		}
+		if x, y := PointDouble(curve, new(big.Int), new(big.Int)); !isInfinity(x, y) {
+			t.Errorf("%s: PointDouble of infinity = (%x, %x)", curve.Params().Name, x, y)
+		}
+	}
+}
+
+func TestAffineSmallCurves(t *testing.T) {
+	// (1016, 0) of the curve of cofactor 2 has a vertical tangent and doubles
+	// to the point at infinity
+	var curve = cofactorCurve(t)
+	var x, y = big.NewInt(1016), big.NewInt(0)
+	if dx, dy := PointDouble(curve, x, y); !isInfinity(dx, dy) {
+		t.Errorf("PointDouble(%v, %v) = (%v, %v), want (0, 0)", x, y, dx, dy)
+	}
+	if sx, sy := PointAdd(curve, x, y, x, y); !isInfinity(sx, sy) {
+		t.Errorf("PointAdd(%v, %v) to itself = (%v, %v), want (0, 0)", x, y, sx, sy)
+	}
+
+	// The toy curve, small enough to check PointAdd against ScalarBaseMult
+	// for every multiple of G
+	var toy = toyCurve(t)
+	var gx, gy = toy.Params().Gx, toy.Params().Gy
+	var px, py = new(big.Int), new(big.Int)
+	for k := int64(1); k <= 500; k++ {
+		px, py = PointAdd(toy, px, py, gx, gy)
+		if wantX, wantY := toy.ScalarBaseMult(big.NewInt(k).Bytes()); px.Cmp(wantX) != 0 || py.Cmp(wantY) != 0 {
+			t.Fatalf("toy: %d additions of G = (%v, %v), want (%v, %v)", k, px, py, wantX, wantY)
+		}
+	}
+}
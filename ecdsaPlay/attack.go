@@ -0,0 +1,47 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// Reports whether two signatures share r, the telltale of k being reused for
+// two messages, after which RecoverPrivateFromReusedNonce recovers the private
+// key. Since R and -R share their x-coordinate, signatures made with k and
+// N - k share r as well, but for a random k that happens with negligible
+// probability
+func HaveSameNonce(sig1, sig2 Signature) bool {
+	return sig1.R != nil && sig2.R != nil && sig1.R.Cmp(sig2.R) == 0
+}
+
+// Demonstrates why k must never be reused. Two signatures made with the same k
+// share r, and from s1 = (z1 + re)/k and s2 = (z2 + re)/k the private key e
+// is recovered as e = (s1*z2 - s2*z1) / (r*(s2 - s1)) mod N
+func RecoverPrivateFromReusedNonce(sig1, sig2 Signature, hash1, hash2 []byte, curve elliptic.Curve) (*big.Int, error) {
+	var n = curve.Params().N
+
+	if !HaveSameNonce(sig1, sig2) {
+		return nil, errors.New("Error: Signatures do not share r, k was not reused")
+	}
+
+	var z1 = HashToInt(hash1, curve)
+	var z2 = HashToInt(hash2, curve)
+
+	// r*(s2 - s1)
+	var denominator = new(big.Int).Sub(sig2.S, sig1.S)
+	denominator.Mul(denominator, sig1.R)
+	denominator.Mod(denominator, n)
+	if denominator.Sign() == 0 {
+		return nil, errors.New("Error: Signatures share s, both sign the same message")
+	}
+
+	// s1*z2 - s2*z1
+	var numerator = new(big.Int).Mul(sig1.S, z2)
+	numerator.Sub(numerator, new(big.Int).Mul(sig2.S, z1))
+
+	var privateKey = numerator.Mul(numerator, inverse(denominator, n))
+	privateKey.Mod(privateKey, n)
+
+	return privateKey, nil
+}
@@ -0,0 +1,155 @@
+package ecdsaplay
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestRecoverPrivateFromReusedNonce(t *testing.T) {
+	var hash1 = sha256.Sum256([]byte("first message"))
+	var hash2 = sha256.Sum256([]byte("second message"))
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r1, s1, err := SignWithK(key, hash1[:], k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2, s2, err := SignWithK(key, hash2[:], k)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sig1, sig2 = Signature{R: r1, S: s1}, Signature{R: r2, S: s2}
+		privateKey, err := RecoverPrivateFromReusedNonce(sig1, sig2, hash1[:], hash2[:], curve)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Params().Name, err)
+		}
+		if privateKey.Cmp(key.Private) != 0 {
+			t.Fatalf("%s: recovered %x, want %x", curve.Params().Name, privateKey, key.Private)
+		}
+
+		// Signatures with distinct k share no r, and those of the same
+		// message share s as well
+		fresh, err := SignV2(key, hash2[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := RecoverPrivateFromReusedNonce(sig1, fresh, hash1[:], hash2[:], curve); err == nil {
+			t.Errorf("%s: recovered a private key from signatures with distinct k", curve.Params().Name)
+		}
+		if _, err := RecoverPrivateFromReusedNonce(sig1, sig1, hash1[:], hash1[:], curve); err == nil {
+			t.Errorf("%s: recovered a private key from a single signature", curve.Params().Name)
+		}
+	}
+}
+
+func TestHaveSameNonce(t *testing.T) {
+	var hash1 = sha256.Sum256([]byte("first message"))
+	var hash2 = sha256.Sum256([]byte("second message"))
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sign = func(messageHash []byte, k *big.Int) Signature {
+			r, s, err := SignWithK(key, messageHash, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return Signature{R: r, S: s}
+		}
+		var other = new(big.Int).Add(k, big.NewInt(1))
+		other.Mod(other, curve.Params().N)
+
+		var tests = []struct {
+			name       string
+			sig1, sig2 Signature
+			want       bool
+		}{
+			{"reused k", sign(hash1[:], k), sign(hash2[:], k), true},
+			{"reused k, same message", sign(hash1[:], k), sign(hash1[:], k), true},
+			{"k and N - k", sign(hash1[:], k), sign(hash2[:], new(big.Int).Sub(curve.Params().N, k)), true},
+			{"distinct k", sign(hash1[:], k), sign(hash2[:], other), false},
+			{"missing r", Signature{S: big.NewInt(1)}, sign(hash2[:], k), false},
+			{"both missing r", Signature{}, Signature{}, false},
+		}
+
+		for _, test := range tests {
+			if got := HaveSameNonce(test.sig1, test.sig2); got != test.want {
+				t.Errorf("%s %s: HaveSameNonce = %v, want %v", curve.Params().Name, test.name, got, test.want)
+			}
+		}
+
+		// Detected reuse is then exploited
+		var sig1, sig2 = sign(hash1[:], k), sign(hash2[:], k)
+		if !HaveSameNonce(sig1, sig2) {
+			t.Fatalf("%s: reuse not detected", curve.Params().Name)
+		}
+		if private, err := RecoverPrivateFromReusedNonce(sig1, sig2, hash1[:], hash2[:], curve); err != nil || private.Cmp(key.Private) != 0 {
+			t.Errorf("%s: RecoverPrivateFromReusedNonce = (%x, %v), want %x", curve.Params().Name, private, err, key.Private)
+		}
+	}
+}
+
+// Reader that yields the same buffer over and over, so that every candidate k
+// of GeneratePreMessageSecretFrom, which reads exactly len(buffer) bytes, is
+// the same
+type replayReader struct {
+	buffer []byte
+	offset int
+}
+
+func (r *replayReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.buffer[r.offset]
+		r.offset = (r.offset + 1) % len(r.buffer)
+	}
+	return len(p), nil
+}
+
+func TestSignReusedNonceRecoversKey(t *testing.T) {
+	var hash1 = sha256.Sum256([]byte("first message"))
+	var hash2 = sha256.Sum256([]byte("second message"))
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+
+		// One candidate k worth of random bytes, replayed for every Sign
+		var buffer = make([]byte, (curve.Params().N.BitLen()+64+7)/8)
+		if _, err := rand.Read(buffer); err != nil {
+			t.Fatal(err)
+		}
+		setNonceSource(t, &replayReader{buffer: buffer})
+
+		r1, s1, err := Sign(key, hash1[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2, s2, err := Sign(key, hash2[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sig1, sig2 = Signature{R: r1, S: s1}, Signature{R: r2, S: s2}
+		if !HaveSameNonce(sig1, sig2) {
+			t.Fatalf("%s: signatures with a replayed nonce source do not share r", curve.Params().Name)
+		}
+		privateKey, err := RecoverPrivateFromReusedNonce(sig1, sig2, hash1[:], hash2[:], curve)
+		if err != nil {
+			t.Fatalf("%s: RecoverPrivateFromReusedNonce: %v", curve.Params().Name, err)
+		}
+		if privateKey.Cmp(key.Private) != 0 {
+			t.Errorf("%s: recovered private key %x, want %x", curve.Params().Name, privateKey, key.Private)
+		}
+	}
+}
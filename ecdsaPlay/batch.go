@@ -0,0 +1,123 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Verifies sigs[i] against pubs[i] and hashes[i] for every i, returning
+// whether all of them are valid along with the indices of those that are not.
+// Slices of differing lengths are rejected as a whole with no indices.
+//
+// Batch verification by random linear combination, i.e. checking
+// sum(a_i R_i) = sum(a_i u_i)G + sum(a_i v_i P_i) for random a_i, needs the
+// full point R_i of every signature. An ECDSA signature only carries the
+// x-coordinate r of R, so the sign of each R_i is unknown and would have to
+// be guessed across 2^len(sigs) combinations. Each signature is therefore
+// verified on its own, which also localizes every failure exactly, while the
+// inverses of s are shared across the batch using BatchInverse
+func VerifyBatch(sigs []Signature, pubs []PublicKey, hashes [][]byte, curve elliptic.Curve) (bool, []int) {
+	if len(sigs) != len(pubs) || len(sigs) != len(hashes) {
+		return false, nil
+	}
+
+	// s^-1 of every signature from a single inversion. Out of range values of
+	// s are passed as 0 and rejected by the range check during verification
+	var n = curve.Params().N
+	var values = make([]*big.Int, len(sigs))
+	for i := range sigs {
+		values[i] = new(big.Int)
+		if s := sigs[i].S; IsValidScalar(s, curve) {
+			values[i].Set(s)
+		}
+	}
+	var inverses = BatchInverse(values, n)
+
+	var failed []int
+	for i := range sigs {
+		if valid, _ := verify(sigs[i].R, sigs[i].S, inverses[i], pubs[i].X, pubs[i].Y, curve, hashes[i], scalarMultCombination); !valid {
+			failed = append(failed, i)
+		}
+	}
+
+	return len(failed) == 0, failed
+}
+
+// Verifies the signature against each of the candidate public keys in turn,
+// returning the index of the first one it is valid for, or -1 and false if
+// there is none. Since u = z/s does not depend on the public key, z, s^-1 and
+// uG are calculated once per curve and only vP is calculated for every key
+func VerifyAny(sig Signature, pubs []PublicKey, messageHash []byte) (int, bool) {
+	var cachedCurve elliptic.Curve
+	var z, invS, uGx, uGy *big.Int
+
+	var combine = func(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
+		if uGx == nil {
+			uGx, uGy = curve.ScalarBaseMult(u.Bytes())
+		}
+		vPx, vPy := curve.ScalarMult(publicKeyX, publicKeyY, v.Bytes())
+		return addPoints(curve, uGx, uGy, vPx, vPy)
+	}
+
+	for i, pub := range pubs {
+		if pub.Curve == nil || checkHashLength(pub.Curve, messageHash) != nil {
+			continue
+		}
+		if pub.Curve != cachedCurve {
+			cachedCurve = pub.Curve
+			z, invS, uGx, uGy = HashToInt(messageHash, pub.Curve), nil, nil, nil
+			if invertible(sig.S, pub.Curve.Params().N) {
+				invS = inverse(sig.S, pub.Curve.Params().N)
+			}
+		}
+
+		if valid, _ := verifyZ(sig.R, sig.S, invS, pub.X, pub.Y, pub.Curve, z, combine); valid {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Calculates the inverse of every value modulo modulus with a single modular
+// inversion using Montgomery's trick: with prefix products
+// c_i = values[0] * ... * values[i], inv = c_last^-1 is calculated once and
+// then values[i]^-1 = inv * c_(i-1) while inv is updated to inv * values[i]
+// walking back from the last value. Values without an inverse (e.g. 0) are
+// left out of the products and yield the sentinel 0 as with inverse
+func BatchInverse(values []*big.Int, modulus *big.Int) []*big.Int {
+	var inverses = make([]*big.Int, len(values))
+	var prefix = make([]*big.Int, len(values))
+
+	// c_i, skipping values without an inverse
+	var product = big.NewInt(1)
+	for i, value := range values {
+		inverses[i] = new(big.Int)
+		prefix[i] = new(big.Int).Set(product)
+		if invertible(value, modulus) {
+			product.Mul(product, value)
+			product.Mod(product, modulus)
+		}
+	}
+
+	var inv = inverse(product, modulus)
+
+	for i := len(values) - 1; i >= 0; i-- {
+		if !invertible(values[i], modulus) {
+			continue
+		}
+
+		// values[i]^-1 = inv * c_(i-1) and inv = inv * values[i]
+		inverses[i].Mul(inv, prefix[i])
+		inverses[i].Mod(inverses[i], modulus)
+		inv.Mul(inv, values[i])
+		inv.Mod(inv, modulus)
+	}
+
+	return inverses
+}
+
+// Whether value has an inverse modulo the prime modulus
+func invertible(value, modulus *big.Int) bool {
+	return value != nil && new(big.Int).Mod(value, modulus).Sign() != 0
+}
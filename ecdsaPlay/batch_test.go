@@ -0,0 +1,155 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// Count signatures over distinct messages, each under its own key
+func signBatch(t testing.TB, curve elliptic.Curve, count int) ([]Signature, []PublicKey, [][]byte) {
+	t.Helper()
+	var sigs = make([]Signature, count)
+	var pubs = make([]PublicKey, count)
+	var hashes = make([][]byte, count)
+	for i := range sigs {
+		var key = generateKey(t, curve)
+		var messageHash = sha256.Sum256([]byte(fmt.Sprintf("message %d", i)))
+		sig, err := SignV2(key, messageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i], pubs[i], hashes[i] = sig, key.PublicKey(), messageHash[:]
+	}
+	return sigs, pubs, hashes
+}
+
+func TestVerifyBatch(t *testing.T) {
+	for _, curve := range nistCurves {
+		var sigs, pubs, hashes = signBatch(t, curve, 8)
+
+		if valid, failed := VerifyBatch(sigs, pubs, hashes, curve); !valid || len(failed) != 0 {
+			t.Fatalf("%s: VerifyBatch = (%v, %v), want (true, [])", curve.Params().Name, valid, failed)
+		}
+
+		var tests = []struct {
+			name   string
+			tamper func(sigs []Signature, pubs []PublicKey, hashes [][]byte)
+			want   []int
+		}{
+			{"bad s at 3", func(sigs []Signature, _ []PublicKey, _ [][]byte) {
+				sigs[3].S = new(big.Int).Add(sigs[3].S, big.NewInt(1))
+			}, []int{3}},
+			{"s = 0 at 0", func(sigs []Signature, _ []PublicKey, _ [][]byte) {
+				sigs[0].S = big.NewInt(0)
+			}, []int{0}},
+			{"swapped keys at 1 and 6", func(_ []Signature, pubs []PublicKey, _ [][]byte) {
+				pubs[1], pubs[6] = pubs[6], pubs[1]
+			}, []int{1, 6}},
+			{"other hash at 7", func(_ []Signature, _ []PublicKey, hashes [][]byte) {
+				hashes[7] = hashes[2]
+			}, []int{7}},
+		}
+
+		for _, test := range tests {
+			var tamperedSigs = append([]Signature{}, sigs...)
+			var tamperedPubs = append([]PublicKey{}, pubs...)
+			var tamperedHashes = append([][]byte{}, hashes...)
+			test.tamper(tamperedSigs, tamperedPubs, tamperedHashes)
+
+			valid, failed := VerifyBatch(tamperedSigs, tamperedPubs, tamperedHashes, curve)
+			if valid || !reflect.DeepEqual(failed, test.want) {
+				t.Errorf("%s %s: VerifyBatch = (%v, %v), want (false, %v)", curve.Params().Name, test.name, valid, failed, test.want)
+			}
+		}
+
+		if valid, failed := VerifyBatch(sigs, pubs[1:], hashes, curve); valid || failed != nil {
+			t.Errorf("%s: VerifyBatch of mismatched lengths = (%v, %v), want (false, nil)", curve.Params().Name, valid, failed)
+		}
+	}
+}
+
+func TestBatchInverse(t *testing.T) {
+	for _, curve := range nistCurves {
+		var n = curve.Params().N
+		var values = []*big.Int{
+			big.NewInt(1),
+			big.NewInt(2),
+			big.NewInt(0),
+			new(big.Int).Sub(n, big.NewInt(1)),
+			generateKey(t, curve).Private,
+			new(big.Int).Set(n),
+			nil,
+			new(big.Int).Add(n, big.NewInt(3)),
+			generateKey(t, curve).Private,
+		}
+
+		var inverses = BatchInverse(values, n)
+		if len(inverses) != len(values) {
+			t.Fatalf("%s: %d inverses of %d values", curve.Params().Name, len(inverses), len(values))
+		}
+		for i, value := range values {
+			// 0, N and nil have no inverse and yield the sentinel 0
+			var want = new(big.Int)
+			if value != nil {
+				if inv := new(big.Int).ModInverse(value, n); inv != nil {
+					want = inv
+				}
+			}
+			if inverses[i].Cmp(want) != 0 {
+				t.Errorf("%s: inverse of values[%d] = %x, want %x", curve.Params().Name, i, inverses[i], want)
+			}
+		}
+	}
+
+	if inverses := BatchInverse(nil, nistCurves[0].Params().N); len(inverses) != 0 {
+		t.Errorf("BatchInverse(nil) = %v, want none", inverses)
+	}
+}
+
+func TestVerifyAny(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pub = key.PublicKey()
+		var other = func() PublicKey { return generateKey(t, curve).PublicKey() }
+		var otherCurve = generateKey(t, elliptic.P224()).PublicKey()
+		if curve == elliptic.P224() {
+			otherCurve = generateKey(t, elliptic.P256()).PublicKey()
+		}
+
+		var tests = []struct {
+			name  string
+			pubs  []PublicKey
+			index int
+		}{
+			{"in the middle", []PublicKey{other(), other(), pub, other(), other()}, 2},
+			{"first", []PublicKey{pub, other()}, 0},
+			{"last", []PublicKey{other(), other(), pub}, 2},
+			{"twice", []PublicKey{other(), pub, pub}, 1},
+			{"after another curve", []PublicKey{otherCurve, other(), pub}, 2},
+			{"after a missing curve", []PublicKey{{X: pub.X, Y: pub.Y}, pub}, 1},
+			{"absent", []PublicKey{other(), other(), other()}, -1},
+			{"empty", nil, -1},
+		}
+
+		for _, test := range tests {
+			index, valid := VerifyAny(sig, test.pubs, testMessageHash[:])
+			if index != test.index || valid != (test.index >= 0) {
+				t.Errorf("%s %s: VerifyAny = (%d, %v), want (%d, %v)", curve.Params().Name, test.name, index, valid, test.index, test.index >= 0)
+			}
+		}
+
+		var otherHash = testMessageHash
+		otherHash[0] ^= 1
+		if index, valid := VerifyAny(sig, []PublicKey{other(), pub}, otherHash[:]); valid || index != -1 {
+			t.Errorf("%s: VerifyAny for another hash = (%d, %v), want (-1, false)", curve.Params().Name, index, valid)
+		}
+	}
+}
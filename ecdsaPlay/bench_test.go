@@ -0,0 +1,212 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"testing"
+)
+
+// Sign for each NIST curve, reusing one key per curve
+func BenchmarkSign(b *testing.B) {
+	for _, curve := range nistCurves {
+		var key = generateKey(b, curve)
+
+		b.Run(curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := Sign(key, testMessageHash[:]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Verify for each NIST curve, reusing one key and signature per curve
+func BenchmarkVerify(b *testing.B) {
+	for _, curve := range nistCurves {
+		var key = generateKey(b, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		})
+	}
+}
+
+// Extended Euclidean inverse versus the Fermat inverse it replaced, for a
+// random s and the order of each NIST curve
+func BenchmarkInverse(b *testing.B) {
+	for _, curve := range nistCurves {
+		var key = generateKey(b, curve)
+		var d, n = key.Private, curve.Params().N
+
+		b.Run("ModInverse/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				inverse(d, n)
+			}
+		})
+		b.Run("Fermat/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				inverseFermat(d, n)
+			}
+		})
+	}
+}
+
+// VerifyBatch versus Verify in a loop, over a batch of 64 P-256 signatures
+func BenchmarkVerifyBatch(b *testing.B) {
+	var curve = elliptic.P256()
+	var sigs, pubs, hashes = signBatch(b, curve, 64)
+
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if valid, _ := VerifyBatch(sigs, pubs, hashes, curve); !valid {
+				b.Fatal("batch does not verify")
+			}
+		}
+	})
+	b.Run("Sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range sigs {
+				if !VerifyV2(sigs[j], pubs[j], hashes[j]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		}
+	})
+}
+
+// w-NAF scalar multiplication versus ScalarMult of the curve, on secp256k1
+// where both use the arithmetic of this package and on P-256 where
+// ScalarMult is that of crypto/elliptic
+func BenchmarkScalarMultWNAF(b *testing.B) {
+	for _, curve := range []elliptic.Curve{Secp256k1(), elliptic.P256()} {
+		var key = generateKey(b, curve)
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run("ScalarMult/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				curve.ScalarMult(key.PublicX, key.PublicY, k.Bytes())
+			}
+		})
+		b.Run("WNAF/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				scalarMultWNAF(curve, key.PublicX, key.PublicY, k)
+			}
+		})
+	}
+}
+
+// VerifyFast versus Verify, on secp256k1 where VerifyFast halves the
+// doublings and on P-256 where Verify uses the arithmetic of crypto/elliptic
+func BenchmarkVerifyFast(b *testing.B) {
+	for _, curve := range []elliptic.Curve{Secp256k1(), elliptic.P256()} {
+		var key = generateKey(b, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run("Verify/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		})
+		b.Run("VerifyFast/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !VerifyFast(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		})
+	}
+}
+
+// Amortized cost of verifying against the same public key with the tables of
+// a KeyVerifier, versus calling Verify every time, on secp256k1 where the
+// tables apply and on P-256 where Verify is used either way
+func BenchmarkVerifierForKey(b *testing.B) {
+	for _, curve := range []elliptic.Curve{Secp256k1(), elliptic.P256()} {
+		var key = generateKey(b, curve)
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			b.Fatal(err)
+		}
+		verifier, err := VerifierForKey(key.PublicKey())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run("Verify/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !Verify(sig.R, sig.S, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		})
+		b.Run("KeyVerifier/"+curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !verifier.Verify(sig, testMessageHash[:]) {
+					b.Fatal("signature does not verify")
+				}
+			}
+		})
+	}
+}
+
+// Verify from GOMAXPROCS goroutines at once over a shared set of keys, which
+// scales with the number of cores only when verification holds no shared
+// state. Run with -race to check for data races as well
+func BenchmarkVerifyParallel(b *testing.B) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), Secp256k1()} {
+		var keys []Key
+		var sigs []Signature
+		var hashes [][]byte
+		for i := 0; i < 16; i++ {
+			var key = generateKey(b, curve)
+			var messageHash = sha256.Sum256([]byte{byte(i)})
+			sig, err := SignV2(key, messageHash[:])
+			if err != nil {
+				b.Fatal(err)
+			}
+			keys = append(keys, key)
+			sigs = append(sigs, sig)
+			hashes = append(hashes, messageHash[:])
+		}
+
+		b.Run(curve.Params().Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					var j = i % len(keys)
+					if !VerifyV2(sigs[j], keys[j].PublicKey(), hashes[j]) {
+						b.Error("valid signature rejected")
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package ecdsaplay
+
+import "errors"
+
+// Signs the message hash with a secp256k1 key and returns the signature as
+// DER with s low-s normalized, as required of transaction signatures by
+// BIP 62 and BIP 66. No sighash type byte is appended; that is up to the
+// caller. The encoding is checked by IsBIP66Signature before it is returned
+func SignBitcoin(key Key, messageHash []byte) ([]byte, error) {
+	if key.Curve != Secp256k1() {
+		return nil, errors.New("Error: Invalid key, Bitcoin signatures require secp256k1")
+	}
+
+	der, err := SignAndEncode(key, messageHash)
+	if err != nil {
+		return nil, err
+	}
+	if !IsBIP66Signature(der) {
+		return nil, errors.New("Error: Invalid signature, encoding is not BIP 66 compliant")
+	}
+	return der, nil
+}
+
+// Reports whether sig is a strict DER signature as required by BIP 66, i.e.
+// passes IsValidSignatureEncoding of the BIP once a sighash type byte were
+// appended: SEQUENCE { INTEGER r, INTEGER s } of at most 72 bytes in total
+// with short form lengths only, and r and s positive and minimally encoded
+func IsBIP66Signature(sig []byte) bool {
+	// 0x30 [total-length] 0x02 [R-length] [R] 0x02 [S-length] [S]
+	if len(sig) < 8 || len(sig) > 72 {
+		return false
+	}
+	if sig[0] != derTagSequence || int(sig[1]) != len(sig)-2 {
+		return false
+	}
+
+	var lenR = int(sig[3])
+	if 5+lenR >= len(sig) {
+		return false
+	}
+	var lenS = int(sig[5+lenR])
+	if lenR+lenS+6 != len(sig) {
+		return false
+	}
+
+	return isBIP66Integer(sig[2:4+lenR]) && isBIP66Integer(sig[4+lenR:])
+}
+
+// Whether b is 0x02 [length] [value] with a positive and minimally encoded
+// value, as BIP 66 requires of r and s
+func isBIP66Integer(b []byte) bool {
+	var value = b[2:]
+	if b[0] != derTagInteger || len(value) == 0 {
+		return false
+	}
+	// Negative
+	if value[0]&0x80 != 0 {
+		return false
+	}
+	// A leading zero byte is only allowed in front of a byte with the high bit
+	// set, which would otherwise be negative
+	if len(value) > 1 && value[0] == 0x00 && value[1]&0x80 == 0 {
+		return false
+	}
+	return true
+}
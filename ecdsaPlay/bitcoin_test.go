@@ -0,0 +1,139 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// IsValidSignatureEncoding of BIP 66, over a signature followed by its
+// sighash type byte, as a reference independent of IsBIP66Signature
+func bip66Reference(sig []byte) bool {
+	if len(sig) < 9 || len(sig) > 73 {
+		return false
+	}
+	if sig[0] != 0x30 || int(sig[1]) != len(sig)-3 {
+		return false
+	}
+	var lenR = int(sig[3])
+	if 5+lenR >= len(sig) {
+		return false
+	}
+	var lenS = int(sig[5+lenR])
+	if lenR+lenS+7 != len(sig) {
+		return false
+	}
+	if sig[2] != 0x02 || lenR == 0 || sig[4]&0x80 != 0 {
+		return false
+	}
+	if lenR > 1 && sig[4] == 0x00 && sig[5]&0x80 == 0 {
+		return false
+	}
+	if sig[lenR+4] != 0x02 || lenS == 0 || sig[lenR+6]&0x80 != 0 {
+		return false
+	}
+	if lenS > 1 && sig[lenR+6] == 0x00 && sig[lenR+7]&0x80 == 0 {
+		return false
+	}
+	return true
+}
+
+func TestSignBitcoin(t *testing.T) {
+	var curve = Secp256k1()
+	var key = generateKey(t, curve)
+	var halfN = new(big.Int).Rsh(curve.Params().N, 1)
+
+	for i := 0; i < 64; i++ {
+		var messageHash = sha256.Sum256([]byte{byte(i)})
+		der, err := SignBitcoin(key, messageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !IsBIP66Signature(der) || !bip66Reference(append(append([]byte{}, der...), 0x01)) {
+			t.Fatalf("signature %x is not strict DER", der)
+		}
+		r, s, err := DecodeSignatureDER(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Cmp(halfN) > 0 {
+			t.Errorf("signature %x: s = %x exceeds N/2", der, s)
+		}
+		if !VerifyStrict(r, s, key.PublicX, key.PublicY, curve, messageHash[:]) {
+			t.Errorf("signature %x does not verify", der)
+		}
+	}
+
+	for _, other := range nistCurves {
+		if _, err := SignBitcoin(generateKey(t, other), testMessageHash[:]); err == nil {
+			t.Errorf("%s: SignBitcoin succeeded", other.Params().Name)
+		}
+	}
+	if _, err := SignBitcoin(Key{Curve: curve}, testMessageHash[:]); err == nil {
+		t.Error("SignBitcoin without a private key succeeded")
+	}
+}
+
+func TestIsBIP66Signature(t *testing.T) {
+	var valid = hexBytes("3006020101020101")
+	var tests = []struct {
+		name string
+		sig  []byte
+		want bool
+	}{
+		{"r = s = 1", valid, true},
+		{"r with the high bit padded", hexBytes("300702020080020101"), true},
+		{"empty", nil, false},
+		{"too short", valid[:7], false},
+		{"trailing data", append(append([]byte{}, valid...), 0x00), false},
+		{"not a SEQUENCE", hexBytes("3106020101020101"), false},
+		{"wrong total length", hexBytes("3007020101020101"), false},
+		{"r not an INTEGER", hexBytes("3006030101020101"), false},
+		{"s not an INTEGER", hexBytes("3006020101030101"), false},
+		{"empty r", hexBytes("30050200020101"), false},
+		{"empty s", hexBytes("30050201010200"), false},
+		{"negative r", hexBytes("3006020181020101"), false},
+		{"negative s", hexBytes("3006020101020181"), false},
+		{"r with excess padding", hexBytes("300702020001020101"), false},
+		{"s with excess padding", hexBytes("300702010102020001"), false},
+		{"r length beyond the end", hexBytes("3006020801020101"), false},
+		{"73 bytes", append([]byte{0x30, 71, 0x02, 33, 0x00, 0x80}, append(make([]byte, 31), append([]byte{0x02, 34, 0x00, 0x80}, make([]byte, 32)...)...)...), false},
+	}
+
+	for _, test := range tests {
+		if got := IsBIP66Signature(test.sig); got != test.want {
+			t.Errorf("%s: IsBIP66Signature(%x) = %v, want %v", test.name, test.sig, got, test.want)
+		}
+		if got := bip66Reference(append(append([]byte{}, test.sig...), 0x01)); got != test.want {
+			t.Errorf("%s: reference of %x = %v, want %v", test.name, test.sig, got, test.want)
+		}
+	}
+
+	// Random corruptions of real signatures must be judged as by the reference
+	var key = generateKey(t, Secp256k1())
+	var random = rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		var messageHash = sha256.Sum256([]byte{byte(i)})
+		der, err := SignBitcoin(key, messageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 100; j++ {
+			var sig = append([]byte{}, der[:random.Intn(len(der)+1)]...)
+			if len(sig) > 0 {
+				sig[random.Intn(len(sig))] ^= byte(1 << uint(random.Intn(8)))
+			}
+			if got, want := IsBIP66Signature(sig), bip66Reference(append(append([]byte{}, sig...), 0x01)); got != want {
+				t.Fatalf("IsBIP66Signature(%x) = %v, reference %v", sig, got, want)
+			}
+		}
+	}
+
+	// DER signatures of other curves are judged the same
+	if der, err := SignAndEncode(generateKey(t, elliptic.P256()), testMessageHash[:]); err != nil || !IsBIP66Signature(der) {
+		t.Errorf("IsBIP66Signature rejected P-256 signature %x, %v", der, err)
+	}
+}
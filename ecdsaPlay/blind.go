@@ -0,0 +1,71 @@
+package ecdsaplay
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Signature as per SignV2 with the operations on secret values blinded by a
+// fresh random factor b within [1, N-1] for every signature. Rather than
+// inverting k and multiplying by the private key e directly,
+//
+//	s = (bz + r(be)) * (bk)^-1 mod N
+//
+// which equals (z + re)/k since the factors of b cancel. The inversion only
+// ever sees bk and the multiplication by r only ever sees be, both uniformly
+// random and unrelated to k and e from one signature to the next, which
+// breaks the correlation between their timing or power draw and the secrets.
+// The scalar multiplication kG is not blinded. The signatures are
+// indistinguishable from those of SignV2
+func SignBlinded(key Key, messageHash []byte) (Signature, error) {
+	if err := checkPrivateKey(key); err != nil {
+		return Signature{}, err
+	}
+	if err := checkSigningHashLength(key.Curve, messageHash); err != nil {
+		return Signature{}, err
+	}
+
+	// A fresh k is selected whenever r = 0 or s = 0
+	for attempt := 0; attempt < maxSignAttempts; attempt++ {
+		randomK, err := GeneratePreMessageSecret(key.Curve)
+		if err != nil {
+			return Signature{}, err
+		}
+		blinding, err := GeneratePreMessageSecret(key.Curve)
+		if err != nil {
+			return Signature{}, err
+		}
+
+		r, s := signWithKBlinded(key, messageHash, randomK, blinding)
+		if r.Sign() != 0 && s.Sign() != 0 {
+			return Signature{R: r, S: s}, nil
+		}
+	}
+
+	return Signature{}, fmt.Errorf("%w, no valid k after %d attempts", ErrZeroSignature, maxSignAttempts)
+}
+
+// Computes r = kG (x-coordinate only) mod N and s = (bz + r(be)) * (bk)^-1
+// mod N for the per-message secret k and blinding factor b
+func signWithKBlinded(key Key, messageHash []byte, k, b *big.Int) (r, s *big.Int) {
+	// r = kG (x-coordinate only) mod N
+	Rx, _ := key.Curve.ScalarBaseMult(scalarBytes(k, key.Curve))
+	r = modN(Rx, key.Curve)
+
+	if r.Sign() == 0 {
+		return r, new(big.Int)
+	}
+
+	// be and bk mod N
+	var blindedE = modN(new(big.Int).Mul(b, key.Private), key.Curve)
+	var blindedK = modN(new(big.Int).Mul(b, k), key.Curve)
+
+	// bz + r(be) mod N
+	s = new(big.Int).Mul(b, HashToInt(messageHash, key.Curve))
+	s = modN(s.Add(s, new(big.Int).Mul(r, blindedE)), key.Curve)
+
+	// s = (bz + r(be))/(bk) mod N
+	s = modN(s.Mul(s, inverse(blindedK, key.Curve.Params().N)), key.Curve)
+
+	return r, s
+}
@@ -0,0 +1,61 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSignWithKBlindedMatchesSignWithK(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var key = generateKey(t, curve)
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantR, wantS := signWithK(key, testMessageHash[:], k)
+
+		// The factors of b cancel for any b, including those at the edges
+		var blindings = []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(n, big.NewInt(1))}
+		for i := 0; i < 20; i++ {
+			b, err := GeneratePreMessageSecret(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			blindings = append(blindings, b)
+		}
+
+		for _, b := range blindings {
+			if r, s := signWithKBlinded(key, testMessageHash[:], k, b); r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+				t.Fatalf("%s b = %x: (r, s) = (%x, %x), want (%x, %x) of signWithK", curve.Params().Name, b, r, s, wantR, wantS)
+			}
+		}
+	}
+}
+
+func TestSignBlinded(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var otherHash = testMessageHash
+		otherHash[0] ^= 1
+
+		for i := 0; i < 20; i++ {
+			sig, err := SignBlinded(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsValidScalar(sig.R, curve) || !IsValidScalar(sig.S, curve) {
+				t.Fatalf("%s: (r, s) = (%x, %x) outside of [1, N-1]", curve.Params().Name, sig.R, sig.S)
+			}
+
+			// Verified exactly as a signature of SignV2
+			var pub = key.PublicKey()
+			if !VerifyV2(sig, pub, testMessageHash[:]) || !VerifyFast(sig.R, sig.S, pub.X, pub.Y, curve, testMessageHash[:]) {
+				t.Fatalf("%s: signature of SignBlinded does not verify", curve.Params().Name)
+			}
+			if valid, err := VerifyDetailed(sig.R, sig.S, pub.X, pub.Y, curve, otherHash[:]); valid || err == nil {
+				t.Fatalf("%s: signature of SignBlinded verifies for another hash", curve.Params().Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ecdsaplay
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+)
+
+// Memoizes the results of VerifyV2 for up to maxSize (signature, public key,
+// message hash) triples, evicting the least recently used one first. Only
+// valid results are cached unless the cache was created with cacheInvalid,
+// since otherwise anyone able to submit signatures could fill the cache with
+// invalid ones and evict the valid entries. A VerifierCache is safe for
+// concurrent use by multiple goroutines
+type VerifierCache struct {
+	maxSize      int
+	cacheInvalid bool
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+// Cached verdict held by the elements of VerifierCache.order
+type cacheEntry struct {
+	key   [sha256.Size]byte
+	valid bool
+}
+
+// Returns an empty VerifierCache holding at most maxSize results, which
+// caches invalid results as well as valid ones when cacheInvalid is set
+func NewVerifierCache(maxSize int, cacheInvalid bool) *VerifierCache {
+	return &VerifierCache{
+		maxSize:      maxSize,
+		cacheInvalid: cacheInvalid,
+		order:        list.New(),
+		entries:      make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Verification as per VerifyV2, returning the cached result when the same
+// triple has been verified before. The lock is not held while verifying, so
+// concurrent calls for different triples do not wait on one another
+func (c *VerifierCache) Verify(sig Signature, pub PublicKey, messageHash []byte) bool {
+	if pub.Curve == nil {
+		return false
+	}
+	var key = cacheKey(sig, pub, messageHash)
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		valid := element.Value.(*cacheEntry).valid
+		c.mu.Unlock()
+		return valid
+	}
+	c.mu.Unlock()
+
+	var valid = VerifyV2(sig, pub, messageHash)
+	if valid || c.cacheInvalid {
+		c.add(key, valid)
+	}
+	return valid
+}
+
+// Number of results currently cached
+func (c *VerifierCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Caches the result for key, evicting the least recently used result when
+// the cache is full
+func (c *VerifierCache) add(key [sha256.Size]byte, valid bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxSize <= 0 {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, valid: valid})
+	if c.order.Len() > c.maxSize {
+		var oldest = c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// SHA-256 over the curve and every value of the triple, each prefixed with
+// its length, and its sign for integers, so that no two distinct triples share
+// an encoding. The curve enters by its parameters as well as its name, as
+// curves created by NewCurve may share a name
+func cacheKey(sig Signature, pub PublicKey, messageHash []byte) [sha256.Size]byte {
+	var h = sha256.New()
+	var write = func(b []byte) {
+		var length = len(b)
+		h.Write([]byte{byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)})
+		h.Write(b)
+	}
+
+	var params = pub.Curve.Params()
+	write([]byte(params.Name))
+	for _, value := range []*big.Int{params.P, curveA(pub.Curve), params.B, params.Gx, params.Gy, params.N, pub.X, pub.Y, sig.R, sig.S} {
+		switch {
+		case value == nil:
+			write([]byte{0})
+		case value.Sign() < 0:
+			write([]byte{1})
+		default:
+			write([]byte{2})
+		}
+		if value != nil {
+			write(value.Bytes())
+		}
+	}
+	write(messageHash)
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
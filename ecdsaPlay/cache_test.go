@@ -0,0 +1,122 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// Whether the cache holds a verdict for the triple, and which
+func cachedVerdict(c *VerifierCache, sig Signature, pub PublicKey, messageHash []byte) (valid, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[cacheKey(sig, pub, messageHash)]
+	if !ok {
+		return false, false
+	}
+	return element.Value.(*cacheEntry).valid, true
+}
+
+func TestVerifierCache(t *testing.T) {
+	var sigs, pubs, hashes = signBatch(t, nistCurves[1], 4)
+	var invalid = Signature{R: sigs[0].R, S: new(big.Int).Add(sigs[0].S, big.NewInt(1))}
+
+	var cache = NewVerifierCache(3, false)
+	for i := 0; i < 3; i++ {
+		if !cache.Verify(sigs[i], pubs[i], hashes[i]) {
+			t.Fatalf("signature %d does not verify", i)
+		}
+	}
+	if cache.Verify(invalid, pubs[0], hashes[0]) {
+		t.Fatal("invalid signature verifies")
+	}
+	if _, ok := cachedVerdict(cache, invalid, pubs[0], hashes[0]); ok || cache.Len() != 3 {
+		t.Fatalf("invalid result cached by default, Len = %d", cache.Len())
+	}
+
+	// A cached verdict is returned without verifying again
+	cache.mu.Lock()
+	cache.entries[cacheKey(sigs[1], pubs[1], hashes[1])].Value.(*cacheEntry).valid = false
+	cache.mu.Unlock()
+	if cache.Verify(sigs[1], pubs[1], hashes[1]) {
+		t.Fatal("Verify did not return the cached verdict")
+	}
+
+	// Signature 0 is the least recently used after using 1 and 2, and is
+	// evicted by signature 3
+	cache.Verify(sigs[2], pubs[2], hashes[2])
+	cache.Verify(sigs[3], pubs[3], hashes[3])
+	if _, ok := cachedVerdict(cache, sigs[0], pubs[0], hashes[0]); ok {
+		t.Error("least recently used result was not evicted")
+	}
+	for i := 1; i < 4; i++ {
+		if _, ok := cachedVerdict(cache, sigs[i], pubs[i], hashes[i]); !ok {
+			t.Errorf("result %d was evicted", i)
+		}
+	}
+	if cache.Len() != 3 {
+		t.Errorf("Len = %d, want 3", cache.Len())
+	}
+
+	var withInvalid = NewVerifierCache(3, true)
+	if withInvalid.Verify(invalid, pubs[0], hashes[0]) {
+		t.Fatal("invalid signature verifies")
+	}
+	if valid, ok := cachedVerdict(withInvalid, invalid, pubs[0], hashes[0]); !ok || valid {
+		t.Errorf("cachedVerdict of the invalid signature = (%v, %v), want (false, true)", valid, ok)
+	}
+
+	var disabled = NewVerifierCache(0, true)
+	if !disabled.Verify(sigs[0], pubs[0], hashes[0]) || disabled.Len() != 0 {
+		t.Errorf("cache of size 0: Len = %d, want 0", disabled.Len())
+	}
+	if cache.Verify(sigs[0], PublicKey{X: pubs[0].X, Y: pubs[0].Y}, hashes[0]) {
+		t.Error("Verify accepted a public key without a curve")
+	}
+}
+
+func TestVerifierCacheConcurrent(t *testing.T) {
+	// Goroutines verifying an overlapping mix of valid and invalid triples
+	// through a cache too small to hold them all, so that lookups, additions
+	// and evictions interleave; run with -race
+	var sigs, pubs, hashes = signBatch(t, nistCurves[1], 8)
+	var invalid = make([]Signature, len(sigs))
+	for i, sig := range sigs {
+		invalid[i] = Signature{R: sig.R, S: new(big.Int).Add(sig.S, big.NewInt(1))}
+	}
+
+	for _, cacheInvalid := range []bool{false, true} {
+		var cache = NewVerifierCache(5, cacheInvalid)
+		var wg sync.WaitGroup
+		var errs = make(chan string, 64)
+		for g := 0; g < 16; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					var i = (g + j) % len(sigs)
+					if !cache.Verify(sigs[i], pubs[i], hashes[i]) {
+						errs <- "valid signature rejected"
+						return
+					}
+					if cache.Verify(invalid[i], pubs[i], hashes[i]) {
+						errs <- "invalid signature accepted"
+						return
+					}
+					if n := cache.Len(); n > 5 {
+						errs <- "cache grew beyond its size"
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("cacheInvalid %v: %s", cacheInvalid, err)
+		}
+		if cache.Len() != 5 {
+			t.Errorf("cacheInvalid %v: Len = %d, want 5", cacheInvalid, cache.Len())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package ecdsaplay
+
+import "io"
+
+// Wraps an io.Reader, counting the bytes read through it, e.g. to measure
+// the entropy GeneratePreMessageSecretFrom or Key.Sign consume: a candidate
+// k takes (N.BitLen() + 64 + 7)/8 bytes, i.e. 40 for P-256. The count is not
+// synchronized, so a CountingReader must not be shared by concurrent readers
+type CountingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+// Returns a CountingReader reading from reader, with a count of 0
+func NewCountingReader(reader io.Reader) *CountingReader {
+	return &CountingReader{reader: reader}
+}
+
+// Reads from the underlying reader, adding the bytes read to the count
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// Number of bytes read so far
+func (c *CountingReader) Count() int64 {
+	return c.count
+}
@@ -0,0 +1,86 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCountingReaderNonce(t *testing.T) {
+	// (N.BitLen() + 64 + 7)/8 bytes per candidate k, as per B.5.1 of FIPS
+	// 186-4
+	var tests = []struct {
+		curve elliptic.Curve
+		want  int64
+	}{
+		{elliptic.P224(), 36},
+		{elliptic.P256(), 40},
+		{elliptic.P384(), 56},
+		{elliptic.P521(), 74},
+		{Secp256k1(), 40},
+	}
+
+	for _, test := range tests {
+		var counting = NewCountingReader(rand.Reader)
+		if got := counting.Count(); got != 0 {
+			t.Fatalf("%s: Count of a new CountingReader = %d, want 0", test.curve.Params().Name, got)
+		}
+		for i := int64(1); i <= 10; i++ {
+			if _, err := GeneratePreMessageSecretFrom(counting, test.curve); err != nil {
+				t.Fatal(err)
+			}
+			if got := counting.Count(); got != i*test.want {
+				t.Fatalf("%s: %d nonces consumed %d bytes, want %d", test.curve.Params().Name, i, got, i*test.want)
+			}
+		}
+	}
+}
+
+func TestCountingReaderP256(t *testing.T) {
+	var curve = elliptic.P256()
+
+	// Short reads are counted as they come, and add up to the 40 bytes of a
+	// candidate k once io.ReadFull is done
+	var counting = NewCountingReader(iotest.OneByteReader(rand.Reader))
+	if _, err := GeneratePreMessageSecretFrom(counting, curve); err != nil {
+		t.Fatal(err)
+	}
+	if got := counting.Count(); got != 40 {
+		t.Errorf("one byte reads: consumed %d bytes, want 40", got)
+	}
+
+	// A discarded all zero candidate costs another 40 bytes
+	var stubbed bytes.Buffer
+	stubbed.Write(make([]byte, 40))
+	stubbed.Write(candidateBuffer(curve, big.NewInt(42)))
+	counting = NewCountingReader(&stubbed)
+	if _, err := GeneratePreMessageSecretFrom(counting, curve); err != nil {
+		t.Fatal(err)
+	}
+	if got := counting.Count(); got != 80 {
+		t.Errorf("one discarded candidate: consumed %d bytes, want 80", got)
+	}
+
+	// A source running dry counts what it delivered
+	counting = NewCountingReader(bytes.NewReader(make([]byte, 25)))
+	if _, err := GeneratePreMessageSecretFrom(counting, curve); err == nil {
+		t.Error("GeneratePreMessageSecretFrom of 25 bytes succeeded")
+	}
+	if got := counting.Count(); got != 25 {
+		t.Errorf("exhausted source: consumed %d bytes, want 25", got)
+	}
+
+	// crypto.Signer takes a single candidate k from the reader it is given
+	var key = generateKey(t, curve)
+	counting = NewCountingReader(rand.Reader)
+	if _, err := key.Sign(counting, testMessageHash[:], crypto.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	if got := counting.Count(); got != 40 {
+		t.Errorf("Key.Sign consumed %d bytes, want 40", got)
+	}
+}
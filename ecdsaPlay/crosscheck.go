@@ -0,0 +1,50 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+)
+
+// Verification as per VerifyDetailed that, as a self-test of the point
+// arithmetic, calculates uG + vP twice: with the curve's own ScalarMult and
+// Add, i.e. crypto/elliptic for the NIST curves and this package's Jacobian
+// arithmetic otherwise, and with double-and-add over the affine PointAdd and
+// PointDouble. Should the two disagree, ErrCrosscheckMismatch is returned
+// whatever the outcome of the verification. The affine calculation takes an
+// inversion per step, so this is meant for debugging rather than production
+func VerifyCrosscheck(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte) (bool, error) {
+	var mismatch error
+
+	var combine linearCombination = func(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
+		x, y = scalarMultCombination(curve, u, v, publicKeyX, publicKeyY)
+
+		uGx, uGy := affineScalarMult(curve, curve.Params().Gx, curve.Params().Gy, u)
+		vPx, vPy := affineScalarMult(curve, publicKeyX, publicKeyY, v)
+		affineX, affineY := PointAdd(curve, uGx, uGy, vPx, vPy)
+
+		if x.Cmp(affineX) != 0 || y.Cmp(affineY) != 0 {
+			mismatch = fmt.Errorf("%w, (%x, %x) versus affine (%x, %x)", ErrCrosscheckMismatch, x, y, affineX, affineY)
+		}
+		return x, y
+	}
+
+	valid, err := verify(r, s, nil, publicKeyX, publicKeyY, curve, messageHash, combine)
+	if mismatch != nil {
+		return false, mismatch
+	}
+	return valid, err
+}
+
+// kP by double-and-add from the most significant bit of k using PointDouble
+// and PointAdd only
+func affineScalarMult(curve elliptic.Curve, Px, Py, k *big.Int) (x, y *big.Int) {
+	x, y = new(big.Int), new(big.Int)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		x, y = PointDouble(curve, x, y)
+		if k.Bit(i) == 1 {
+			x, y = PointAdd(curve, x, y, Px, Py)
+		}
+	}
+	return x, y
+}
@@ -0,0 +1,70 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestVerifyCrosscheckMatchesVerify(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1(), genericCurve(t, elliptic.P256()), LadderCurve(elliptic.P256())) {
+		var key = generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var otherHash = append([]byte{}, testMessageHash[:]...)
+		otherHash[0] ^= 0x01
+
+		var tests = []struct {
+			name        string
+			r, s        *big.Int
+			messageHash []byte
+		}{
+			{"valid", r, s, testMessageHash[:]},
+			{"other hash", r, s, otherHash},
+			{"r + 1", new(big.Int).Add(r, big.NewInt(1)), s, testMessageHash[:]},
+			{"N - s", r, new(big.Int).Sub(curve.Params().N, s), testMessageHash[:]},
+			{"s = 0", r, new(big.Int), testMessageHash[:]},
+			{"empty hash", r, s, nil},
+		}
+
+		for _, test := range tests {
+			var want = Verify(test.r, test.s, key.PublicX, key.PublicY, curve, test.messageHash)
+			valid, err := VerifyCrosscheck(test.r, test.s, key.PublicX, key.PublicY, curve, test.messageHash)
+			if errors.Is(err, ErrCrosscheckMismatch) {
+				t.Errorf("%s %s: VerifyCrosscheck: %v", curve.Params().Name, test.name, err)
+			}
+			if valid != want {
+				t.Errorf("%s %s: VerifyCrosscheck = %v (%v), want %v", curve.Params().Name, test.name, valid, err, want)
+			}
+			if valid != (err == nil) {
+				t.Errorf("%s %s: VerifyCrosscheck = %v with err = %v", curve.Params().Name, test.name, valid, err)
+			}
+		}
+	}
+}
+
+// P-256 whose ScalarMult is off by G, as a faulty point arithmetic would be
+type offByGCurve struct {
+	elliptic.Curve
+}
+
+func (c offByGCurve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	x, y = c.Curve.ScalarMult(x, y, k)
+	return c.Curve.Add(x, y, c.Params().Gx, c.Params().Gy)
+}
+
+func TestVerifyCrosscheckMismatch(t *testing.T) {
+	var key = generateKey(t, elliptic.P256())
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var curve = offByGCurve{elliptic.P256()}
+	if valid, err := VerifyCrosscheck(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]); valid || !errors.Is(err, ErrCrosscheckMismatch) {
+		t.Errorf("VerifyCrosscheck = %v, %v, want false, %v", valid, err, ErrCrosscheckMismatch)
+	}
+}
@@ -0,0 +1,338 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// Short Weierstrass curve y^2 = x^3 + ax + b over the prime field of order P.
+// crypto/elliptic only implements the NIST curves, where a = -3, hence this
+// implementation carries a explicitly and performs its own point arithmetic
+// in Jacobian coordinates (X, Y, Z), representing the affine point
+// (X/Z^2, Y/Z^3). As with crypto/elliptic the point at infinity is (0, 0)
+// in affine coordinates
+type weierstrassCurve struct {
+	params *elliptic.CurveParams
+	a      *big.Int
+
+	// Cofactor h = #E / N, or nil when h = 1 as for every curve of this
+	// package so far
+	cofactor *big.Int
+
+	// Whether ScalarMult uses scalarMultLadder, see LadderCurve
+	ladder bool
+}
+
+var (
+	initSecp256k1 sync.Once
+	secp256k1     *weierstrassCurve
+)
+
+// Returns the curve secp256k1 used by Bitcoin and Ethereum, defined in
+// section 2.4.1 of SEC 2: Recommended Elliptic Curve Domain Parameters
+// as y^2 = x^3 + 7, i.e. a = 0 and b = 7
+func Secp256k1() elliptic.Curve {
+	initSecp256k1.Do(func() {
+		var params = &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+		params.P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+		params.N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+		params.B = big.NewInt(7)
+		params.Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+		params.Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+		secp256k1 = &weierstrassCurve{params: params, a: new(big.Int)}
+	})
+	return secp256k1
+}
+
+// Returns the short Weierstrass curve y^2 = x^3 + ax + b over the prime field
+// of order p with generator G = (gx, gy) of prime order n and cofactor h, for
+// use with every function of this package in place of a crypto/elliptic
+// curve. The parameters are checked for consistency, i.e. a non-singular
+// curve with G on it and nG the point at infinity, but not for security: p
+// and n are not tested for primality, nor is n checked to be large
+func NewCurve(name string, p, a, b, gx, gy, n, h *big.Int) (elliptic.Curve, error) {
+	for _, value := range []*big.Int{p, a, b, gx, gy, n, h} {
+		if value == nil {
+			return nil, errors.New("Error: Invalid curve, missing parameter")
+		}
+	}
+	if p.Cmp(big.NewInt(3)) <= 0 || n.Sign() <= 0 || h.Sign() <= 0 {
+		return nil, errors.New("Error: Invalid curve, p must exceed 3 and n and h must be positive")
+	}
+
+	var params = &elliptic.CurveParams{
+		Name:    name,
+		BitSize: p.BitLen(),
+		P:       new(big.Int).Set(p),
+		N:       new(big.Int).Set(n),
+		B:       new(big.Int).Mod(b, p),
+		Gx:      new(big.Int).Set(gx),
+		Gy:      new(big.Int).Set(gy),
+	}
+	var curve = &weierstrassCurve{params: params, a: new(big.Int).Mod(a, p)}
+	if h.Cmp(big.NewInt(1)) != 0 {
+		curve.cofactor = new(big.Int).Set(h)
+	}
+
+	// 4a^3 + 27b^2 != 0 mod p, otherwise the curve is singular
+	var discriminant = new(big.Int).Exp(curve.a, big.NewInt(3), p)
+	discriminant.Lsh(discriminant, 2)
+	discriminant.Add(discriminant, new(big.Int).Mul(big.NewInt(27), new(big.Int).Mul(params.B, params.B)))
+	if discriminant.Mod(discriminant, p).Sign() == 0 {
+		return nil, errors.New("Error: Invalid curve, singular as 4a^3 + 27b^2 = 0")
+	}
+
+	if !curve.IsOnCurve(params.Gx, params.Gy) {
+		return nil, fmt.Errorf("%w, invalid generator", ErrPointNotOnCurve)
+	}
+	if nGx, nGy := curve.ScalarBaseMult(params.N.Bytes()); nGx.Sign() != 0 || nGy.Sign() != 0 {
+		return nil, errors.New("Error: Invalid curve, nG is not the point at infinity")
+	}
+
+	return curve, nil
+}
+
+func (curve *weierstrassCurve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+// y^2 = x^3 + ax + b mod P, with both coordinates within the field
+func (curve *weierstrassCurve) IsOnCurve(x, y *big.Int) bool {
+	var p = curve.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+
+	var y2 = new(big.Int).Mul(y, y)
+	y2.Mod(y2, p)
+
+	return y2.Cmp(curveEquation(curve, x)) == 0
+}
+
+func (curve *weierstrassCurve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	var p1 = curve.toJacobian(x1, y1)
+	var p2 = curve.toJacobian(x2, y2)
+	return curve.toAffine(curve.addJacobian(p1, p2))
+}
+
+func (curve *weierstrassCurve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	return curve.toAffine(curve.doubleJacobian(curve.toJacobian(x1, y1)))
+}
+
+// Double-and-add over the big-endian bits of k, or the Montgomery ladder for
+// curves returned by LadderCurve
+func (curve *weierstrassCurve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	if curve.ladder {
+		return scalarMultLadder(curve, x1, y1, new(big.Int).SetBytes(k))
+	}
+
+	var base = curve.toJacobian(x1, y1)
+	var result = jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			result = curve.doubleJacobian(result)
+			if (b>>uint(bit))&1 == 1 {
+				result = curve.addJacobian(result, base)
+			}
+		}
+	}
+
+	return curve.toAffine(result)
+}
+
+func (curve *weierstrassCurve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return curve.ScalarMult(curve.params.Gx, curve.params.Gy, k)
+}
+
+// Point (X, Y, Z) in Jacobian coordinates; where, Z = 0 is the point at infinity
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func (curve *weierstrassCurve) toJacobian(x, y *big.Int) jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return jacobianPoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+// (X/Z^2, Y/Z^3), or (0, 0) for the point at infinity
+func (curve *weierstrassCurve) toAffine(point jacobianPoint) (x, y *big.Int) {
+	if point.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	var p = curve.params.P
+	var zInv = new(big.Int).ModInverse(point.z, p)
+	var zInv2 = new(big.Int).Mul(zInv, zInv)
+
+	x = new(big.Int).Mul(point.x, zInv2)
+	x.Mod(x, p)
+
+	y = new(big.Int).Mul(point.y, zInv2)
+	y.Mul(y, zInv)
+	y.Mod(y, p)
+
+	return x, y
+}
+
+// Point doubling in Jacobian coordinates with
+// S = 4XY^2, M = 3X^2 + aZ^4, X' = M^2 - 2S, Y' = M(S - X') - 8Y^4, Z' = 2YZ
+func (curve *weierstrassCurve) doubleJacobian(point jacobianPoint) jacobianPoint {
+	if point.z.Sign() == 0 || point.y.Sign() == 0 {
+		return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+
+	var p = curve.params.P
+
+	var yy = new(big.Int).Mul(point.y, point.y)
+	yy.Mod(yy, p)
+
+	var s = new(big.Int).Mul(point.x, yy)
+	s.Lsh(s, 2)
+	s.Mod(s, p)
+
+	var zz = new(big.Int).Mul(point.z, point.z)
+	var m = new(big.Int).Mul(point.x, point.x)
+	m.Mul(m, big.NewInt(3))
+	m.Add(m, new(big.Int).Mul(curve.a, new(big.Int).Mul(zz, zz)))
+	m.Mod(m, p)
+
+	var x = new(big.Int).Mul(m, m)
+	x.Sub(x, new(big.Int).Lsh(s, 1))
+	x.Mod(x, p)
+
+	var y = new(big.Int).Sub(s, x)
+	y.Mul(y, m)
+	y.Sub(y, new(big.Int).Lsh(new(big.Int).Mul(yy, yy), 3))
+	y.Mod(y, p)
+
+	var z = new(big.Int).Mul(point.y, point.z)
+	z.Lsh(z, 1)
+	z.Mod(z, p)
+
+	return jacobianPoint{x, y, z}
+}
+
+// Point addition in Jacobian coordinates with U1 = X1Z2^2, U2 = X2Z1^2,
+// S1 = Y1Z2^3, S2 = Y2Z1^3, H = U2 - U1, R = S2 - S1,
+// X' = R^2 - H^3 - 2U1H^2, Y' = R(U1H^2 - X') - S1H^3, Z' = HZ1Z2
+func (curve *weierstrassCurve) addJacobian(p1, p2 jacobianPoint) jacobianPoint {
+	if p1.z.Sign() == 0 {
+		return p2
+	}
+	if p2.z.Sign() == 0 {
+		return p1
+	}
+
+	var p = curve.params.P
+
+	var z1z1 = new(big.Int).Mul(p1.z, p1.z)
+	var z2z2 = new(big.Int).Mul(p2.z, p2.z)
+
+	var u1 = new(big.Int).Mul(p1.x, z2z2)
+	u1.Mod(u1, p)
+	var u2 = new(big.Int).Mul(p2.x, z1z1)
+	u2.Mod(u2, p)
+
+	var s1 = new(big.Int).Mul(p1.y, z2z2)
+	s1.Mul(s1, p2.z)
+	s1.Mod(s1, p)
+	var s2 = new(big.Int).Mul(p2.y, z1z1)
+	s2.Mul(s2, p1.z)
+	s2.Mod(s2, p)
+
+	if u1.Cmp(u2) == 0 {
+		if s1.Cmp(s2) != 0 {
+			// P + (-P) = point at infinity
+			return jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+		}
+		return curve.doubleJacobian(p1)
+	}
+
+	var h = new(big.Int).Sub(u2, u1)
+	var r = new(big.Int).Sub(s2, s1)
+
+	var hh = new(big.Int).Mul(h, h)
+	hh.Mod(hh, p)
+	var hhh = new(big.Int).Mul(hh, h)
+	hhh.Mod(hhh, p)
+	var u1hh = new(big.Int).Mul(u1, hh)
+	u1hh.Mod(u1hh, p)
+
+	var x = new(big.Int).Mul(r, r)
+	x.Sub(x, hhh)
+	x.Sub(x, new(big.Int).Lsh(u1hh, 1))
+	x.Mod(x, p)
+
+	var y = new(big.Int).Sub(u1hh, x)
+	y.Mul(y, r)
+	y.Sub(y, new(big.Int).Mul(s1, hhh))
+	y.Mod(y, p)
+
+	var z = new(big.Int).Mul(h, p1.z)
+	z.Mul(z, p2.z)
+	z.Mod(z, p)
+
+	return jacobianPoint{x, y, z}
+}
+
+// Curves known by name to this package: P-224, P-256, P-384 and P-521 of
+// crypto/elliptic, each used with a hash of at least its size, and Secp256k1
+func SupportedCurves() []elliptic.Curve {
+	return []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), Secp256k1()}
+}
+
+// Whether keys can be generated on curve: one of SupportedCurves, or a curve
+// implemented by this package such as those of NewCurve and LadderCurve.
+// Other implementations of elliptic.Curve, including a bare
+// *elliptic.CurveParams, whose generic arithmetic assumes a = -3, are not
+func isSupportedCurve(curve elliptic.Curve) bool {
+	if _, ok := curve.(*weierstrassCurve); ok {
+		return true
+	}
+	for _, supported := range SupportedCurves() {
+		if curve == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Curves known by name to this package, as given by Params().Name
+func curveByName(name string) (elliptic.Curve, bool) {
+	switch name {
+	case elliptic.P224().Params().Name:
+		return elliptic.P224(), true
+	case elliptic.P256().Params().Name:
+		return elliptic.P256(), true
+	case elliptic.P384().Params().Name:
+		return elliptic.P384(), true
+	case elliptic.P521().Params().Name:
+		return elliptic.P521(), true
+	case Secp256k1().Params().Name:
+		return Secp256k1(), true
+	}
+	return nil, false
+}
+
+// Name of the known curve whose parameters equal those of curve, or "unknown"
+// if there is none. Parameters are compared rather than names, as curves
+// created by NewCurve may reuse the name of a known curve
+func knownCurveName(curve elliptic.Curve) string {
+	var params = curve.Params()
+	var a = new(big.Int).Mod(curveA(curve), params.P)
+	for _, known := range SupportedCurves() {
+		var knownParams = known.Params()
+		var knownA = new(big.Int).Mod(curveA(known), knownParams.P)
+		if params.P.Cmp(knownParams.P) == 0 && a.Cmp(knownA) == 0 && params.B.Cmp(knownParams.B) == 0 &&
+			params.Gx.Cmp(knownParams.Gx) == 0 && params.Gy.Cmp(knownParams.Gy) == 0 && params.N.Cmp(knownParams.N) == 0 {
+			return knownParams.Name
+		}
+	}
+	return "unknown"
+}
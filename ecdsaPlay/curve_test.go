@@ -0,0 +1,242 @@
+package ecdsaplay
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSecp256k1SignVerify(t *testing.T) {
+	var curve = Secp256k1()
+	for i := 0; i < 20; i++ {
+		var key = generateKey(t, curve)
+		if !curve.IsOnCurve(key.PublicX, key.PublicY) {
+			t.Fatalf("public key (%x, %x) is not on the curve", key.PublicX, key.PublicY)
+		}
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+			t.Fatal("signature does not verify")
+		}
+		var otherHash = sha256.Sum256([]byte("Take the green pill!"))
+		if Verify(r, s, key.PublicX, key.PublicY, curve, otherHash[:]) {
+			t.Fatal("signature verifies for another hash")
+		}
+	}
+}
+
+func TestSecp256k1KnownAnswer(t *testing.T) {
+	// RFC 6979 signature of "Satoshi Nakamoto" with SHA-256 under the private
+	// key 1, as given by libsecp256k1 in low-s form and checked against the
+	// secp256k1 of OpenSSL through Python's cryptography
+	var curve = Secp256k1()
+	var wantR = hexInt("934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8")
+	var wantS = hexInt("2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5")
+
+	var key = Key{Private: big.NewInt(1), Curve: curve}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+	if key.PublicX.Cmp(curve.Params().Gx) != 0 || key.PublicY.Cmp(curve.Params().Gy) != 0 {
+		t.Fatalf("public key of 1 = (%x, %x), want G", key.PublicX, key.PublicY)
+	}
+
+	var messageHash = sha256.Sum256([]byte("Satoshi Nakamoto"))
+	if !Verify(wantR, wantS, key.PublicX, key.PublicY, curve, messageHash[:]) {
+		t.Fatal("Verify rejected the known signature")
+	}
+
+	r, s, err := SignDeterministic(key, messageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(wantR) != 0 || NormalizeS(s, curve).Cmp(wantS) != 0 {
+		t.Fatalf("(r, s) = (%x, %x), want (%x, %x) up to the sign of s", r, s, wantR, wantS)
+	}
+}
+
+// Curve of NewCurve with the parameters of the given crypto/elliptic curve
+func genericCurve(t *testing.T, curve elliptic.Curve) elliptic.Curve {
+	t.Helper()
+	var params = curve.Params()
+	generic, err := NewCurve(params.Name+" generic", params.P, big.NewInt(-3), params.B, params.Gx, params.Gy, params.N, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return generic
+}
+
+func TestNewCurveMatchesElliptic(t *testing.T) {
+	for _, curve := range nistCurves {
+		var generic = genericCurve(t, curve)
+
+		for i := 0; i < 10; i++ {
+			k, err := GeneratePreMessageSecret(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x, y := generic.ScalarBaseMult(k.Bytes())
+			wantX, wantY := curve.ScalarBaseMult(k.Bytes())
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Fatalf("%s: ScalarBaseMult(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, k, x, y, wantX, wantY)
+			}
+			if !generic.IsOnCurve(x, y) {
+				t.Fatalf("%s: kG is not on the generic curve", curve.Params().Name)
+			}
+
+			x2, y2 := generic.Add(x, y, curve.Params().Gx, curve.Params().Gy)
+			wantX2, wantY2 := curve.Add(wantX, wantY, curve.Params().Gx, curve.Params().Gy)
+			if x2.Cmp(wantX2) != 0 || y2.Cmp(wantY2) != 0 {
+				t.Fatalf("%s: Add differs from crypto/elliptic", curve.Params().Name)
+			}
+			x2, y2 = generic.Double(x, y)
+			wantX2, wantY2 = curve.Double(wantX, wantY)
+			if x2.Cmp(wantX2) != 0 || y2.Cmp(wantY2) != 0 {
+				t.Fatalf("%s: Double differs from crypto/elliptic", curve.Params().Name)
+			}
+		}
+
+		// A signature over either curve verifies over the other
+		var key = generateKey(t, generic)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+			t.Errorf("%s: signature over the generic curve does not verify over crypto/elliptic", curve.Params().Name)
+		}
+		if !ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: key.PublicX, Y: key.PublicY}, testMessageHash[:], r, s) {
+			t.Errorf("%s: ecdsa.Verify rejected the signature over the generic curve", curve.Params().Name)
+		}
+
+		key = generateKey(t, curve)
+		if r, s, err = Sign(key, testMessageHash[:]); err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, generic, testMessageHash[:]) {
+			t.Errorf("%s: signature over crypto/elliptic does not verify over the generic curve", curve.Params().Name)
+		}
+	}
+}
+
+func TestNewCurveInvalid(t *testing.T) {
+	var p256 = elliptic.P256().Params()
+	var one = big.NewInt(1)
+
+	var tests = []struct {
+		name                  string
+		p, a, b, gx, gy, n, h *big.Int
+	}{
+		{"missing b", p256.P, big.NewInt(-3), nil, p256.Gx, p256.Gy, p256.N, one},
+		{"p = 3", big.NewInt(3), big.NewInt(-3), p256.B, p256.Gx, p256.Gy, p256.N, one},
+		{"n = 0", p256.P, big.NewInt(-3), p256.B, p256.Gx, p256.Gy, new(big.Int), one},
+		{"h = 0", p256.P, big.NewInt(-3), p256.B, p256.Gx, p256.Gy, p256.N, new(big.Int)},
+		{"singular", big.NewInt(32831), big.NewInt(0), big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(7), one},
+		{"G off the curve", p256.P, big.NewInt(-3), p256.B, p256.Gx, new(big.Int).Add(p256.Gy, one), p256.N, one},
+		{"wrong order", p256.P, big.NewInt(-3), p256.B, p256.Gx, p256.Gy, new(big.Int).Sub(p256.N, one), one},
+	}
+
+	for _, test := range tests {
+		if curve, err := NewCurve(test.name, test.p, test.a, test.b, test.gx, test.gy, test.n, test.h); err == nil {
+			t.Errorf("%s: NewCurve = %v, want an error", test.name, curve.Params())
+		}
+	}
+}
+
+func TestKeyCurveName(t *testing.T) {
+	// P-256 parameters with another b, as a custom *elliptic.CurveParams
+	var custom = *elliptic.P256().Params()
+	custom.Name = "P-256"
+	custom.B = new(big.Int).Add(custom.B, big.NewInt(1))
+
+	var tests = []struct {
+		name     string
+		curve    elliptic.Curve
+		want     string
+		bitSize  int
+		byteSize int
+	}{
+		{"P-224", elliptic.P224(), "P-224", 224, 28},
+		{"P-256", elliptic.P256(), "P-256", 256, 32},
+		{"P-384", elliptic.P384(), "P-384", 384, 48},
+		{"P-521", elliptic.P521(), "P-521", 521, 66},
+		{"secp256k1", Secp256k1(), "secp256k1", 256, 32},
+		{"LadderCurve of P-256", LadderCurve(elliptic.P256()), "P-256", 256, 32},
+		{"NewCurve of the P-384 parameters", genericCurve(t, elliptic.P384()), "P-384", 384, 48},
+		{"toy curve", toyCurve(t), "unknown", 16, 2},
+		{"custom CurveParams named P-256", &custom, "unknown", 256, 32},
+		{"no curve", nil, "unknown", 0, 0},
+	}
+
+	for _, test := range tests {
+		var key = Key{Curve: test.curve}
+		if got := key.CurveName(); got != test.want {
+			t.Errorf("%s: CurveName = %q, want %q", test.name, got, test.want)
+		}
+		if got := key.BitSize(); got != test.bitSize {
+			t.Errorf("%s: BitSize = %d, want %d", test.name, got, test.bitSize)
+		}
+		if got := key.ByteSize(); got != test.byteSize {
+			t.Errorf("%s: ByteSize = %d, want %d", test.name, got, test.byteSize)
+		}
+	}
+}
+
+func TestGenerateKeyPairSupportedCurves(t *testing.T) {
+	var names = map[string]bool{}
+	for _, curve := range SupportedCurves() {
+		var name = curve.Params().Name
+		names[name] = true
+
+		key, err := GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			t.Fatalf("%s: GeneratePrivatePublicKeyPair: %v", name, err)
+		}
+		if key.Curve != curve || !curve.IsOnCurve(key.PublicX, key.PublicY) {
+			t.Errorf("%s: generated public key (%x, %x) not on the curve", name, key.PublicX, key.PublicY)
+		}
+		if err := key.Validate(); err != nil {
+			t.Errorf("%s: Validate of a generated key: %v", name, err)
+		}
+
+		// Each curve signs with a hash of at least its size
+		var messageHash = sha512.Sum512([]byte("Take the red pill!"))
+		r, s, err := Sign(key, messageHash[:])
+		if err != nil {
+			t.Fatalf("%s: Sign: %v", name, err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, curve, messageHash[:]) {
+			t.Errorf("%s: signature does not verify", name)
+		}
+	}
+	for _, name := range []string{"P-224", "P-256", "P-384", "P-521", "secp256k1"} {
+		if !names[name] {
+			t.Errorf("SupportedCurves lacks %s", name)
+		}
+	}
+
+	// Curves of this package beyond SupportedCurves are accepted as well
+	for _, curve := range []elliptic.Curve{genericCurve(t, elliptic.P256()), LadderCurve(elliptic.P256()), toyCurve(t)} {
+		if _, err := GeneratePrivatePublicKeyPair(curve); err != nil {
+			t.Errorf("%s: GeneratePrivatePublicKeyPair: %v", curve.Params().Name, err)
+		}
+	}
+
+	var unsupported = []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"nil", nil},
+		{"P-256 CurveParams", elliptic.P256().Params()},
+		{"secp256k1 CurveParams", Secp256k1().Params()},
+	}
+	for _, test := range unsupported {
+		if _, err := GeneratePrivatePublicKeyPair(test.curve); !errors.Is(err, ErrUnsupportedCurve) {
+			t.Errorf("%s: GeneratePrivatePublicKeyPair err = %v, want %v", test.name, err, ErrUnsupportedCurve)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// DER tags used by ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+const (
+	derTagInteger  = 0x02
+	derTagSequence = 0x30
+)
+
+// Policy of DecodeSignatureDERWithPolicy and VerifyDERWithPolicy for data
+// following the DER SEQUENCE
+type TrailingDataPolicy int
+
+const (
+	// Trailing data is an error, as in DecodeSignatureDER
+	RejectTrailing TrailingDataPolicy = iota
+
+	// Trailing data is ignored, for ecosystems that append e.g. a sighash
+	// type byte or a further signature after the SEQUENCE
+	AllowTrailing
+)
+
+// Encodes signature (r, s) as the DER encoding of SEQUENCE { INTEGER r, INTEGER s }
+// as used by X.509, TLS and OpenSSL. Integers with the high bit set are prefixed
+// with a zero byte so that they remain positive
+func EncodeSignatureDER(r, s *big.Int) ([]byte, error) {
+	if r == nil || s == nil || r.Sign() <= 0 || s.Sign() <= 0 {
+		return nil, errors.New("Error: Invalid signature, r and s must be positive")
+	}
+
+	var body = appendDERInteger(nil, r)
+	body = appendDERInteger(body, s)
+
+	var der = []byte{derTagSequence}
+	der = appendDERLength(der, len(body))
+	return append(der, body...), nil
+}
+
+// Decodes the DER encoding of SEQUENCE { INTEGER r, INTEGER s } into signature
+// (r, s). Any data following either INTEGER or the SEQUENCE itself is rejected,
+// as are encodings other than the unique DER one: lengths and integers must be
+// minimally encoded, so that e.g. r with a superfluous leading zero byte or
+// with the high bit set and no zero byte (i.e. negative) is an error
+func DecodeSignatureDER(der []byte) (r, s *big.Int, err error) {
+	r, s, _, err = decodeSignatureDER(der, false, RejectTrailing)
+	return r, s, err
+}
+
+// Decodes a signature as per DecodeSignatureDER, additionally accepting the
+// non-minimal BER encodings produced by some legacy signers, i.e. integers
+// padded with superfluous leading zero bytes and long form lengths that fit
+// the short form. Negative integers and trailing data are still rejected.
+// Since such a signature has several encodings, it must not be used where
+// the encoding itself is relied upon, e.g. as an identifier
+func LenientDecodeSignatureDER(der []byte) (r, s *big.Int, err error) {
+	r, s, _, err = decodeSignatureDER(der, true, RejectTrailing)
+	return r, s, err
+}
+
+// Decodes a signature as per DecodeSignatureDER, with data following the
+// SEQUENCE handled as per policy, and returns the number of bytes of der the
+// SEQUENCE takes up. With AllowTrailing, a consumed below len(der) shows that
+// further data, e.g. a concatenated signature, follows. Data following s
+// within the SEQUENCE is rejected by either policy
+func DecodeSignatureDERWithPolicy(der []byte, policy TrailingDataPolicy) (r, s *big.Int, consumed int, err error) {
+	return decodeSignatureDER(der, false, policy)
+}
+
+// Decodes a signature as per DecodeSignatureDER, or as per
+// LenientDecodeSignatureDER when lenient is set, handling trailing data as
+// per policy
+func decodeSignatureDER(der []byte, lenient bool, policy TrailingDataPolicy) (r, s *big.Int, consumed int, err error) {
+	body, rest, err := readDERElement(der, derTagSequence, lenient)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(rest) != 0 && policy != AllowTrailing {
+		return nil, nil, 0, errors.New("Error: Invalid signature, trailing data after DER sequence")
+	}
+
+	r, body, err = readDERInteger(body, lenient)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	s, body, err = readDERInteger(body, lenient)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(body) != 0 {
+		return nil, nil, 0, errors.New("Error: Invalid signature, trailing data after s")
+	}
+
+	return r, s, len(der) - len(rest), nil
+}
+
+// Signs the message hash as per Sign, applies low-s normalization and returns
+// the DER encoded signature
+func SignAndEncode(key Key, messageHash []byte) ([]byte, error) {
+	r, s, err := Sign(key, messageHash)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSignatureDER(r, NormalizeS(s, key.Curve))
+}
+
+// Decodes a DER encoded signature and verifies it against the public key as
+// per VerifyV2. A malformed encoding is returned as an error, whereas a well
+// formed signature that does not verify is reported as false with no error
+func VerifyDER(derSig []byte, pub PublicKey, messageHash []byte) (bool, error) {
+	r, s, err := DecodeSignatureDER(derSig)
+	if err != nil {
+		return false, err
+	}
+	return VerifyV2(Signature{R: r, S: s}, pub, messageHash), nil
+}
+
+// Verification as per VerifyDER, with data following the SEQUENCE handled as
+// per policy
+func VerifyDERWithPolicy(derSig []byte, pub PublicKey, messageHash []byte, policy TrailingDataPolicy) (bool, error) {
+	r, s, _, err := DecodeSignatureDERWithPolicy(derSig, policy)
+	if err != nil {
+		return false, err
+	}
+	return VerifyV2(Signature{R: r, S: s}, pub, messageHash), nil
+}
+
+// Converts a DER encoded signature, as per DecodeSignatureDER, to the fixed
+// width r || s of EncodeSignatureFixed for the curve, e.g. for a JWS. r and
+// s must be within [1, N-1], as otherwise they may not fit the fixed width
+func DERToFixed(der []byte, curve elliptic.Curve) ([]byte, error) {
+	r, s, err := DecodeSignatureDER(der)
+	if err != nil {
+		return nil, err
+	}
+	var sig = Signature{R: r, S: s}
+	if err = checkSignatureRange(sig, curve); err != nil {
+		return nil, err
+	}
+	return EncodeSignatureFixed(sig, curve), nil
+}
+
+// Converts a fixed width r || s signature, which must be exactly
+// SignatureByteLen(curve) bytes long, to DER as per EncodeSignatureDER. r and
+// s must be within [1, N-1]
+func FixedToDER(fixed []byte, curve elliptic.Curve) ([]byte, error) {
+	sig, err := DecodeSignatureFixed(fixed, curve)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSignatureRange(sig, curve); err != nil {
+		return nil, err
+	}
+	return EncodeSignatureDER(sig.R, sig.S)
+}
+
+// Rejects r or s outside of [1, N-1] with ErrROutOfRange or ErrSOutOfRange
+func checkSignatureRange(sig Signature, curve elliptic.Curve) error {
+	if !IsValidScalar(sig.R, curve) {
+		return ErrROutOfRange
+	}
+	if !IsValidScalar(sig.S, curve) {
+		return ErrSOutOfRange
+	}
+	return nil
+}
+
+// Appends x as a DER INTEGER, with a zero byte prefix if the high bit is set
+func appendDERInteger(der []byte, x *big.Int) []byte {
+	var b = x.Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0x00}, b...)
+	}
+	der = append(der, derTagInteger)
+	der = appendDERLength(der, len(b))
+	return append(der, b...)
+}
+
+// Appends a DER length in short form (< 128) or long form
+func appendDERLength(der []byte, length int) []byte {
+	if length < 0x80 {
+		return append(der, byte(length))
+	}
+
+	var b []byte
+	for ; length > 0; length >>= 8 {
+		b = append([]byte{byte(length)}, b...)
+	}
+	der = append(der, 0x80|byte(len(b)))
+	return append(der, b...)
+}
+
+// Reads a single DER element with the expected tag and returns its contents
+// along with whatever follows it. Long form lengths that are not minimally
+// encoded are only accepted when lenient is set
+func readDERElement(der []byte, tag byte, lenient bool) (contents, rest []byte, err error) {
+	if len(der) < 2 || der[0] != tag {
+		return nil, nil, errors.New("Error: Invalid DER, unexpected tag")
+	}
+
+	var length = int(der[1])
+	var offset = 2
+
+	if length&0x80 != 0 {
+		// Long form: the low bits hold the number of length bytes that follow,
+		// which must be minimal and must not encode a length below 128
+		var lengthBytes = length & 0x7f
+		if lengthBytes == 0 || lengthBytes > 4 || len(der) < offset+lengthBytes || (der[offset] == 0 && !lenient) {
+			return nil, nil, errors.New("Error: Invalid DER, malformed length")
+		}
+
+		length = 0
+		for _, b := range der[offset : offset+lengthBytes] {
+			length = length<<8 | int(b)
+		}
+		offset += lengthBytes
+
+		if length < 0 {
+			return nil, nil, errors.New("Error: Invalid DER, malformed length")
+		}
+		if length < 0x80 && !lenient {
+			return nil, nil, errors.New("Error: Invalid DER, length not minimally encoded")
+		}
+	}
+
+	if len(der)-offset < length {
+		return nil, nil, errors.New("Error: Invalid DER, truncated element")
+	}
+
+	return der[offset : offset+length], der[offset+length:], nil
+}
+
+// Reads a positive, minimally encoded DER INTEGER, or a positive INTEGER with
+// any number of leading zero bytes when lenient is set
+func readDERInteger(der []byte, lenient bool) (x *big.Int, rest []byte, err error) {
+	b, rest, err := readDERElement(der, derTagInteger, lenient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, nil, errors.New("Error: Invalid DER, empty integer")
+	}
+	if b[0]&0x80 != 0 {
+		return nil, nil, errors.New("Error: Invalid DER, negative integer")
+	}
+	if len(b) > 1 && b[0] == 0x00 && b[1]&0x80 == 0 && !lenient {
+		return nil, nil, errors.New("Error: Invalid DER, integer not minimally encoded")
+	}
+
+	x = new(big.Int).SetBytes(b)
+	if x.Sign() == 0 {
+		return nil, nil, errors.New("Error: Invalid signature, r and s must be positive")
+	}
+
+	return x, rest, nil
+}
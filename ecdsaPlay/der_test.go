@@ -0,0 +1,469 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// ECDSA-Sig-Value as marshalled by encoding/asn1
+type asn1Signature struct {
+	R, S *big.Int
+}
+
+func TestEncodeSignatureDERMatchesASN1(t *testing.T) {
+	var pairs = []struct {
+		r, s *big.Int
+	}{
+		{big.NewInt(1), big.NewInt(1)},
+		{big.NewInt(127), big.NewInt(128)},
+		{big.NewInt(255), big.NewInt(256)},
+		{big.NewInt(0x7fff), big.NewInt(0x8000)},
+		// r and s of RFC 6979 A.2.5, "sample" and SHA-256, s with the high bit set
+		{hexInt("EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716"), hexInt("F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8")},
+		// s of RFC 6979 A.2.5, "test" and SHA-256, with a leading zero byte
+		{hexInt("F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367"), hexInt("019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083")},
+		// 66 byte values as of P-521, so that the SEQUENCE needs a long form length
+		{new(big.Int).Lsh(big.NewInt(1), 520), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 521), big.NewInt(1))},
+	}
+
+	for _, pair := range pairs {
+		der, err := EncodeSignatureDER(pair.r, pair.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := asn1.Marshal(asn1Signature{pair.r, pair.s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(der, want) {
+			t.Errorf("EncodeSignatureDER(%x, %x) = %x, want %x", pair.r, pair.s, der, want)
+		}
+
+		r, s, err := DecodeSignatureDER(want)
+		if err != nil {
+			t.Fatalf("DecodeSignatureDER(%x): %v", want, err)
+		}
+		if r.Cmp(pair.r) != 0 || s.Cmp(pair.s) != 0 {
+			t.Errorf("DecodeSignatureDER(%x) = (%x, %x), want (%x, %x)", want, r, s, pair.r, pair.s)
+		}
+	}
+}
+
+func TestDecodeSignatureDERSigned(t *testing.T) {
+	for _, curve := range nistCurves {
+		var key = generateKey(t, curve)
+		for i := 0; i < 20; i++ {
+			der, err := SignAndEncode(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var parsed asn1Signature
+			if rest, err := asn1.Unmarshal(der, &parsed); err != nil || len(rest) != 0 {
+				t.Fatalf("%s: encoding/asn1 rejected %x: %v", curve.Params().Name, der, err)
+			}
+			r, s, err := DecodeSignatureDER(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Cmp(parsed.R) != 0 || s.Cmp(parsed.S) != 0 {
+				t.Fatalf("%s: DecodeSignatureDER(%x) differs from encoding/asn1", curve.Params().Name, der)
+			}
+		}
+	}
+}
+
+func TestDecodeSignatureDERInvalid(t *testing.T) {
+	var valid, _ = EncodeSignatureDER(big.NewInt(0x1234), big.NewInt(0x80))
+
+	var tests = []struct {
+		name string
+		der  []byte
+	}{
+		{"empty", nil},
+		{"trailing garbage", append(append([]byte{}, valid...), 0x00)},
+		{"truncated", valid[:len(valid)-1]},
+		{"wrong tag", append([]byte{0x31}, valid[1:]...)},
+		{"data after s", []byte{0x30, 0x09, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01}},
+		{"negative r", []byte{0x30, 0x06, 0x02, 0x01, 0x80, 0x02, 0x01, 0x01}},
+		{"zero r", []byte{0x30, 0x06, 0x02, 0x01, 0x00, 0x02, 0x01, 0x01}},
+		{"empty s", []byte{0x30, 0x05, 0x02, 0x01, 0x01, 0x02, 0x00}},
+		{"missing sign byte", []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0xff}},
+		{"superfluous zero byte", []byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01}},
+		{"long form length below 128", []byte{0x30, 0x81, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01}},
+	}
+
+	for _, test := range tests {
+		if r, s, err := DecodeSignatureDER(test.der); err == nil {
+			t.Errorf("%s: DecodeSignatureDER(%x) = (%v, %v), want an error", test.name, test.der, r, s)
+		}
+	}
+}
+
+func TestEncodeSignatureDERInvalid(t *testing.T) {
+	for _, pair := range [][2]*big.Int{{nil, big.NewInt(1)}, {big.NewInt(1), nil}, {big.NewInt(0), big.NewInt(1)}, {big.NewInt(1), big.NewInt(-1)}} {
+		if der, err := EncodeSignatureDER(pair[0], pair[1]); err == nil {
+			t.Errorf("EncodeSignatureDER(%v, %v) = %x, want an error", pair[0], pair[1], der)
+		}
+	}
+}
+
+func TestVerifyDER(t *testing.T) {
+	var key = generateKey(t, nistCurves[1])
+	der, err := SignAndEncode(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var otherHash = testMessageHash
+	otherHash[0] ^= 1
+
+	// r of 33 bytes, encoded correctly but beyond N
+	var tooLarge, _ = EncodeSignatureDER(new(big.Int).Lsh(big.NewInt(1), 257), big.NewInt(1))
+
+	var tests = []struct {
+		name      string
+		der       []byte
+		hash      []byte
+		want      bool
+		malformed bool
+	}{
+		{"valid", der, testMessageHash[:], true, false},
+		{"other hash", der, otherHash[:], false, false},
+		{"r beyond N", tooLarge, testMessageHash[:], false, false},
+		{"truncated", der[:len(der)-1], testMessageHash[:], false, true},
+		{"truncated to the header", der[:2], testMessageHash[:], false, true},
+		{"integer length beyond the SEQUENCE", append([]byte{0x30, 0x06, 0x02, 0x7f}, der[4:8]...), testMessageHash[:], false, true},
+		{"superfluous leading zeros", []byte{0x30, 0x08, 0x02, 0x03, 0x00, 0x00, 0x01, 0x02, 0x01, 0x01}, testMessageHash[:], false, true},
+		{"empty", nil, testMessageHash[:], false, true},
+	}
+
+	for _, test := range tests {
+		valid, err := VerifyDER(test.der, key.PublicKey(), test.hash)
+		if valid != test.want || (err != nil) != test.malformed {
+			t.Errorf("%s: VerifyDER(%x) = (%v, %v), want %v and malformed %v", test.name, test.der, valid, err, test.want, test.malformed)
+		}
+	}
+}
+
+func TestSignAndEncodeSeeded(t *testing.T) {
+	// With k pinned to that of the first P-256, SHA-256 case of the CAVP
+	// SigGen vectors, the DER must be that of the published r and the low
+	// form of s, as marshalled by encoding/asn1 and accepted by crypto/ecdsa
+	var record = readCAVP(t, "testdata/SigGen_P256_SHA256.rsp", "[P-256,SHA-256]")[0]
+	var curve = elliptic.P256()
+	var key = Key{Private: hexInt(record["d"]), Curve: curve}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+	var messageHash = sha256.Sum256(hexBytes(record["Msg"]))
+	var k = hexInt(record["k"])
+
+	setNonceSource(t, bytes.NewReader(candidateBuffer(curve, new(big.Int).Sub(k, big.NewInt(1)))))
+	der, err := SignAndEncode(key, messageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r, s = hexInt(record["R"]), hexInt(record["S"])
+	want, err := asn1.Marshal(asn1Signature{r, NormalizeS(s, curve)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(der, want) {
+		t.Fatalf("SignAndEncode = %x, want %x", der, want)
+	}
+	if !ecdsa.VerifyASN1(&ecdsa.PublicKey{Curve: curve, X: key.PublicX, Y: key.PublicY}, messageHash[:], der) {
+		t.Fatal("ecdsa.VerifyASN1 rejected the signature")
+	}
+}
+
+func TestSignASN1MatchesEncodeSignatureDER(t *testing.T) {
+	// crypto/ecdsa draws its own k, so its DER is compared to the encoding
+	// of the same r and s
+	for _, curve := range nistCurves {
+		var key = generateKey(t, curve)
+		der, err := ecdsa.SignASN1(rand.Reader, key.ecdsaPrivateKey(), testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, s, err := DecodeSignatureDER(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, err := EncodeSignatureDER(r, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, der) {
+			t.Errorf("%s: EncodeSignatureDER = %x, want %x of ecdsa.SignASN1", curve.Params().Name, encoded, der)
+		}
+
+		ours, err := SignAndEncode(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ecdsa.VerifyASN1(&key.ecdsaPrivateKey().PublicKey, testMessageHash[:], ours) {
+			t.Errorf("%s: ecdsa.VerifyASN1 rejected the signature of SignAndEncode", curve.Params().Name)
+		}
+		if _, s, _ := DecodeSignatureDER(ours); NormalizeS(s, curve).Cmp(s) != 0 {
+			t.Errorf("%s: SignAndEncode gave a high s", curve.Params().Name)
+		}
+	}
+}
+
+func TestDecodeSignatureDERNonCanonical(t *testing.T) {
+	// A minimally encoded signature of a real r and s, then the same values
+	// rewritten in the non-minimal forms of legacy BER signers
+	var key = generateKey(t, elliptic.P256())
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimal, err := EncodeSignatureDER(r, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// INTEGER of the given contents, and SEQUENCE of r then s
+	var integer = func(contents []byte) []byte {
+		return append([]byte{0x02, byte(len(contents))}, contents...)
+	}
+	var sequence = func(r, s []byte) []byte {
+		var body = append(append([]byte{}, r...), s...)
+		return append([]byte{0x30, byte(len(body))}, body...)
+	}
+	var minimalR, minimalS = minimal[2 : 4+int(minimal[3])], minimal[4+int(minimal[3]):]
+	var paddedR = integer(append([]byte{0x00, 0x00}, r.Bytes()...))
+	var paddedS = integer(append([]byte{0x00, 0x00}, s.Bytes()...))
+	var longForm = append([]byte{0x30, 0x81, minimal[1]}, minimal[2:]...)
+
+	var tests = []struct {
+		name    string
+		der     []byte
+		strict  bool
+		lenient bool
+	}{
+		{"minimal", minimal, true, true},
+		{"padded r", sequence(paddedR, minimalS), false, true},
+		{"padded s", sequence(minimalR, paddedS), false, true},
+		{"padded r and s", sequence(paddedR, paddedS), false, true},
+		{"long form length below 128", longForm, false, true},
+		{"negative r", sequence(integer([]byte{0x80, 0x01}), minimalS), false, false},
+		{"negative s", sequence(minimalR, integer(append([]byte{0xff}, s.Bytes()...))), false, false},
+		{"zero r", sequence(integer([]byte{0x00, 0x00}), minimalS), false, false},
+		{"trailing data", append(append([]byte{}, minimal...), 0x00), false, false},
+	}
+
+	for _, test := range tests {
+		for _, decode := range []struct {
+			name string
+			fn   func([]byte) (*big.Int, *big.Int, error)
+			want bool
+		}{
+			{"DecodeSignatureDER", DecodeSignatureDER, test.strict},
+			{"LenientDecodeSignatureDER", LenientDecodeSignatureDER, test.lenient},
+		} {
+			gotR, gotS, err := decode.fn(test.der)
+			if (err == nil) != decode.want {
+				t.Errorf("%s: %s(%x) err = %v, want accepted %v", test.name, decode.name, test.der, err, decode.want)
+				continue
+			}
+			if err == nil && (gotR.Cmp(r) != 0 || gotS.Cmp(s) != 0) {
+				t.Errorf("%s: %s(%x) = (%x, %x), want (%x, %x)", test.name, decode.name, test.der, gotR, gotS, r, s)
+			}
+		}
+	}
+}
+
+func TestDecodeSignatureDERWithPolicy(t *testing.T) {
+	var key = generateKey(t, elliptic.P256())
+	der1, err := SignAndEncode(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	der2, err := SignAndEncode(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var join = func(parts ...[]byte) []byte {
+		var b []byte
+		for _, part := range parts {
+			b = append(b, part...)
+		}
+		return b
+	}
+
+	var tests = []struct {
+		name  string
+		der   []byte
+		trail int
+	}{
+		{"no trailing data", der1, 0},
+		{"sighash byte", join(der1, []byte{0x01}), 1},
+		{"junk", join(der1, []byte("junk")), 4},
+		{"second signature", join(der1, der2), len(der2)},
+	}
+
+	for _, test := range tests {
+		r, s, consumed, err := DecodeSignatureDERWithPolicy(test.der, RejectTrailing)
+		if (err == nil) != (test.trail == 0) {
+			t.Errorf("%s: RejectTrailing err = %v, want error %v", test.name, err, test.trail != 0)
+		}
+		if err == nil && consumed != len(der1) {
+			t.Errorf("%s: RejectTrailing consumed = %d, want %d", test.name, consumed, len(der1))
+		}
+		valid, err := VerifyDERWithPolicy(test.der, key.PublicKey(), testMessageHash[:], RejectTrailing)
+		if valid != (test.trail == 0) || (err == nil) != (test.trail == 0) {
+			t.Errorf("%s: VerifyDERWithPolicy RejectTrailing = (%v, %v)", test.name, valid, err)
+		}
+
+		r, s, consumed, err = DecodeSignatureDERWithPolicy(test.der, AllowTrailing)
+		if err != nil {
+			t.Fatalf("%s: AllowTrailing: %v", test.name, err)
+		}
+		if consumed != len(der1) || len(test.der)-consumed != test.trail {
+			t.Errorf("%s: AllowTrailing consumed = %d of %d, want %d", test.name, consumed, len(test.der), len(der1))
+		}
+		if wantR, wantS, _ := DecodeSignatureDER(der1); r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+			t.Errorf("%s: AllowTrailing = (%x, %x), want (%x, %x)", test.name, r, s, wantR, wantS)
+		}
+		if valid, err := VerifyDERWithPolicy(test.der, key.PublicKey(), testMessageHash[:], AllowTrailing); !valid || err != nil {
+			t.Errorf("%s: VerifyDERWithPolicy AllowTrailing = (%v, %v), want (true, nil)", test.name, valid, err)
+		}
+	}
+
+	// Concatenated signatures are split by consumed
+	var rest = join(der1, der2)
+	for _, want := range [][]byte{der1, der2} {
+		_, _, consumed, err := DecodeSignatureDERWithPolicy(rest, AllowTrailing)
+		if err != nil || !bytes.Equal(rest[:consumed], want) {
+			t.Fatalf("split %x, want %x (%v)", rest[:consumed], want, err)
+		}
+		rest = rest[consumed:]
+	}
+	if len(rest) != 0 {
+		t.Errorf("%x left after splitting", rest)
+	}
+
+	// Data after s within the SEQUENCE is rejected by either policy
+	var inside = []byte{0x30, 0x09, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01}
+	for _, policy := range []TrailingDataPolicy{RejectTrailing, AllowTrailing} {
+		if _, _, _, err := DecodeSignatureDERWithPolicy(inside, policy); err == nil {
+			t.Errorf("policy %d accepted data after s", policy)
+		}
+	}
+}
+
+func TestDERFixedRoundTrip(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var sigs = []Signature{
+			{R: big.NewInt(1), S: big.NewInt(1)},
+			{R: big.NewInt(0x80), S: big.NewInt(0x7f)},
+			{R: new(big.Int).Sub(n, big.NewInt(1)), S: new(big.Int).Rsh(n, 8)},
+		}
+		var key = generateKey(t, curve)
+		for i := 0; i < 5; i++ {
+			var messageHash = sha256.Sum256([]byte{byte(i)})
+			sig, err := SignV2(key, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			sigs = append(sigs, sig)
+		}
+
+		for _, sig := range sigs {
+			der, err := EncodeSignatureDER(sig.R, sig.S)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixed = EncodeSignatureFixed(sig, curve)
+
+			// DER to fixed and back
+			gotFixed, err := DERToFixed(der, curve)
+			if err != nil {
+				t.Fatalf("%s: DERToFixed(%x): %v", curve.Params().Name, der, err)
+			}
+			if !bytes.Equal(gotFixed, fixed) || len(gotFixed) != SignatureByteLen(curve) {
+				t.Errorf("%s: DERToFixed(%x) = %x, want %x", curve.Params().Name, der, gotFixed, fixed)
+			}
+			if back, err := FixedToDER(gotFixed, curve); err != nil || !bytes.Equal(back, der) {
+				t.Errorf("%s: FixedToDER(DERToFixed(%x)) = %x, %v", curve.Params().Name, der, back, err)
+			}
+
+			// Fixed to DER and back
+			gotDER, err := FixedToDER(fixed, curve)
+			if err != nil {
+				t.Fatalf("%s: FixedToDER(%x): %v", curve.Params().Name, fixed, err)
+			}
+			var parsed asn1Signature
+			if _, err := asn1.Unmarshal(gotDER, &parsed); err != nil || parsed.R.Cmp(sig.R) != 0 || parsed.S.Cmp(sig.S) != 0 {
+				t.Errorf("%s: FixedToDER(%x) = %x, which encoding/asn1 parses as %v, %v", curve.Params().Name, fixed, gotDER, parsed, err)
+			}
+			if back, err := DERToFixed(gotDER, curve); err != nil || !bytes.Equal(back, fixed) {
+				t.Errorf("%s: DERToFixed(FixedToDER(%x)) = %x, %v", curve.Params().Name, fixed, back, err)
+			}
+		}
+	}
+}
+
+func TestDERFixedInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var n = curve.Params().N
+	var valid = Signature{R: big.NewInt(42), S: big.NewInt(43)}
+	var validDER, _ = EncodeSignatureDER(valid.R, valid.S)
+	var validFixed = EncodeSignatureFixed(valid, curve)
+
+	var derOf = func(r, s *big.Int) []byte {
+		der, err := asn1.Marshal(asn1Signature{r, s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+
+	var derTests = []struct {
+		name string
+		der  []byte
+		want error
+	}{
+		{"empty", nil, nil},
+		{"truncated", validDER[:len(validDER)-1], nil},
+		{"trailing data", append(append([]byte{}, validDER...), 0x00), nil},
+		// Rejected by DecodeSignatureDER already
+		{"r = 0", derOf(big.NewInt(0), valid.S), nil},
+		{"s = N", derOf(valid.R, n), ErrSOutOfRange},
+		// A P-384 sized r does not fit the 64 bytes of P-256
+		{"r of P-384", derOf(new(big.Int).Sub(elliptic.P384().Params().N, big.NewInt(1)), valid.S), ErrROutOfRange},
+	}
+	for _, test := range derTests {
+		fixed, err := DERToFixed(test.der, curve)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: DERToFixed(%x) = %x, %v, want an error %v", test.name, test.der, fixed, err, test.want)
+		}
+	}
+
+	var fixedTests = []struct {
+		name  string
+		fixed []byte
+		want  error
+	}{
+		{"empty", nil, nil},
+		{"63 bytes", validFixed[:63], nil},
+		{"65 bytes", append(append([]byte{}, validFixed...), 0x00), nil},
+		{"DER", validDER, nil},
+		{"r = 0", EncodeSignatureFixed(Signature{R: new(big.Int), S: valid.S}, curve), ErrROutOfRange},
+		{"s = N", EncodeSignatureFixed(Signature{R: valid.R, S: n}, curve), ErrSOutOfRange},
+	}
+	for _, test := range fixedTests {
+		der, err := FixedToDER(test.fixed, curve)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: FixedToDER(%x) = %x, %v, want an error %v", test.name, test.fixed, der, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ecdsaplay
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Derives the child key at index from the parent key by hardened derivation:
+// the tweak t = SHA-512(e || index) mod N, with the private key e padded to
+// the byte size of N and index as 4 big-endian bytes, gives the child private
+// key (e + t) mod N. Without the parent private key neither the child private
+// key nor the child public key can be derived. Identical parents and indices
+// always give identical children
+func DeriveChild(parent Key, index uint32) (Key, error) {
+	if err := checkPrivateKey(parent); err != nil {
+		return Key{}, err
+	}
+
+	var data = parent.PrivateBytes()
+	data = append(data, byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+	var digest = sha512.Sum512(data)
+
+	var n = parent.Curve.Params().N
+	var tweak = new(big.Int).SetBytes(digest[:])
+	tweak.Mod(tweak, n)
+
+	// (e + t) mod N = 0 with negligible probability, as in BIP 32 the index
+	// is then unusable
+	child, err := TweakPrivate(parent, tweak)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w, index %d", err, index)
+	}
+	return child, nil
+}
+
+// Adds tweak to the private key, giving the key pair with private key
+// (e + tweak) mod N and public key P + tweak*G, as calculated by TweakPublic
+// from the public key alone. This is the basis of non-hardened derivation as
+// in BIP 32 and of stealth addresses. A result of 0 is rejected
+func TweakPrivate(priv Key, tweak *big.Int) (Key, error) {
+	if err := checkPrivateKey(priv); err != nil {
+		return Key{}, err
+	}
+	if tweak == nil {
+		return Key{}, errors.New("Error: Invalid tweak, missing")
+	}
+
+	var n = priv.Curve.Params().N
+	var tweaked = new(big.Int).Add(priv.Private, tweak)
+	tweaked.Mod(tweaked, n)
+	if tweaked.Sign() == 0 {
+		return Key{}, fmt.Errorf("%w, tweaked key is 0", ErrInvalidPrivateKey)
+	}
+
+	return NewKeyFromScalar(priv.Curve, tweaked)
+}
+
+// Adds tweak*G to the public key, giving the public key of
+// TweakPrivate(priv, tweak) for the key pair priv of pub without knowledge
+// of its private key. A result at the point at infinity is rejected with
+// ErrIdentityPublicKey
+func TweakPublic(pub PublicKey, tweak *big.Int) (PublicKey, error) {
+	if pub.Curve == nil {
+		return PublicKey{}, errors.New("Error: Invalid public key, missing curve")
+	}
+	if err := validatePublicPoint(pub.Curve, pub.X, pub.Y); err != nil {
+		return PublicKey{}, err
+	}
+	if tweak == nil {
+		return PublicKey{}, errors.New("Error: Invalid tweak, missing")
+	}
+
+	var reduced = new(big.Int).Mod(tweak, pub.Curve.Params().N)
+	if reduced.Sign() == 0 {
+		return PublicKey{X: new(big.Int).Set(pub.X), Y: new(big.Int).Set(pub.Y), Curve: pub.Curve}, nil
+	}
+
+	tGx, tGy := pub.Curve.ScalarBaseMult(reduced.Bytes())
+	x, y := addPoints(pub.Curve, pub.X, pub.Y, tGx, tGy)
+	if isInfinity(x, y) {
+		return PublicKey{}, ErrIdentityPublicKey
+	}
+
+	return PublicKey{X: x, Y: y, Curve: pub.Curve}, nil
+}
@@ -0,0 +1,141 @@
+package ecdsaplay
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestDeriveChild(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var parent = generateKey(t, curve)
+		var parentPrivate = new(big.Int).Set(parent.Private)
+
+		var children = make(map[string]uint32)
+		for _, index := range []uint32{0, 1, 2, 0x7fffffff, 0x80000000, 0xffffffff} {
+			child, err := DeriveChild(parent, index)
+			if err != nil {
+				t.Fatalf("%s index %d: %v", curve.Params().Name, index, err)
+			}
+
+			// child private = (e + SHA-512(e || index)) mod N
+			var byteLen = scalarByteLen(curve)
+			var data = make([]byte, byteLen+4)
+			parent.Private.FillBytes(data[:byteLen])
+			binary.BigEndian.PutUint32(data[byteLen:], index)
+			var digest = sha512.Sum512(data)
+			var want = new(big.Int).SetBytes(digest[:])
+			want.Add(want, parent.Private)
+			want.Mod(want, n)
+			if child.Private.Cmp(want) != 0 {
+				t.Errorf("%s index %d: child private = %x, want %x", curve.Params().Name, index, child.Private, want)
+			}
+
+			// child public = child private * G
+			wantX, wantY := curve.ScalarBaseMult(child.Private.Bytes())
+			if child.PublicX.Cmp(wantX) != 0 || child.PublicY.Cmp(wantY) != 0 || child.Curve != curve {
+				t.Errorf("%s index %d: child public (%x, %x), want (%x, %x)", curve.Params().Name, index, child.PublicX, child.PublicY, wantX, wantY)
+			}
+
+			// The same parent and index give the same child
+			again, err := DeriveChild(parent, index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.Private.Cmp(child.Private) != 0 || again.PublicX.Cmp(child.PublicX) != 0 || again.PublicY.Cmp(child.PublicY) != 0 {
+				t.Errorf("%s index %d: two derivations differ", curve.Params().Name, index)
+			}
+
+			if other, ok := children[child.Private.String()]; ok {
+				t.Errorf("%s: indices %d and %d give the same child", curve.Params().Name, other, index)
+			}
+			children[child.Private.String()] = index
+
+			r, s, err := Sign(child, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, child.PublicX, child.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s index %d: signature of the child does not verify", curve.Params().Name, index)
+			}
+		}
+
+		if parent.Private.Cmp(parentPrivate) != 0 {
+			t.Errorf("%s: DeriveChild changed the parent", curve.Params().Name)
+		}
+	}
+
+	var public = generateKey(t, nistCurves[1])
+	public.Private = nil
+	if _, err := DeriveChild(public, 0); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("DeriveChild without a private key: err = %v, want %v", err, ErrInvalidPrivateKey)
+	}
+}
+
+func TestTweakCommutes(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var key = generateKey(t, curve)
+		random, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var tests = []struct {
+			name  string
+			tweak *big.Int
+		}{
+			{"random", random},
+			{"1", big.NewInt(1)},
+			{"0", new(big.Int)},
+			{"N", new(big.Int).Set(n)},
+			{"N + random", new(big.Int).Add(n, random)},
+			{"-1", big.NewInt(-1)},
+			{"-random", new(big.Int).Neg(random)},
+		}
+
+		for _, test := range tests {
+			tweaked, err := TweakPrivate(key, test.tweak)
+			if err != nil {
+				t.Fatalf("%s tweak %s: TweakPrivate: %v", curve.Params().Name, test.name, err)
+			}
+			pub, err := TweakPublic(key.PublicKey(), test.tweak)
+			if err != nil {
+				t.Fatalf("%s tweak %s: TweakPublic: %v", curve.Params().Name, test.name, err)
+			}
+
+			// TweakPublic(P, t) == TweakPrivate(e, t).PublicKey()
+			var want = tweaked.PublicKey()
+			if pub.X.Cmp(want.X) != 0 || pub.Y.Cmp(want.Y) != 0 || pub.Curve != want.Curve {
+				t.Errorf("%s tweak %s: TweakPublic = (%x, %x), want (%x, %x)", curve.Params().Name, test.name, pub.X, pub.Y, want.X, want.Y)
+			}
+
+			var private = new(big.Int).Add(key.Private, test.tweak)
+			if private.Mod(private, n); tweaked.Private.Cmp(private) != 0 {
+				t.Errorf("%s tweak %s: TweakPrivate = %x, want (e + tweak) mod N = %x", curve.Params().Name, test.name, tweaked.Private, private)
+			}
+		}
+
+		// A tweak of -e gives the private key 0 and the point at infinity
+		var minusE = new(big.Int).Neg(key.Private)
+		if _, err := TweakPrivate(key, minusE); !errors.Is(err, ErrInvalidPrivateKey) {
+			t.Errorf("%s: TweakPrivate by -e: err = %v, want %v", curve.Params().Name, err, ErrInvalidPrivateKey)
+		}
+		if _, err := TweakPublic(key.PublicKey(), minusE); !errors.Is(err, ErrIdentityPublicKey) {
+			t.Errorf("%s: TweakPublic by -e: err = %v, want %v", curve.Params().Name, err, ErrIdentityPublicKey)
+		}
+
+		if _, err := TweakPrivate(key, nil); err == nil {
+			t.Errorf("%s: TweakPrivate accepted a missing tweak", curve.Params().Name)
+		}
+		if _, err := TweakPublic(key.PublicKey(), nil); err == nil {
+			t.Errorf("%s: TweakPublic accepted a missing tweak", curve.Params().Name)
+		}
+		if _, err := TweakPublic(PublicKey{X: new(big.Int), Y: new(big.Int), Curve: curve}, random); !errors.Is(err, ErrIdentityPublicKey) {
+			t.Errorf("%s: TweakPublic of (0, 0): err = %v, want %v", curve.Params().Name, err, ErrIdentityPublicKey)
+		}
+	}
+}
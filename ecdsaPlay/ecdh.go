@@ -0,0 +1,27 @@
+package ecdsaplay
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Elliptic Curve Diffie-Hellman shared secret; where, the peer's public point
+// is multiplied by our private scalar and the x-coordinate of the result,
+// as a fixed-size big-endian value, is the shared secret. Both parties arrive
+// at the same point since e1(e2 G) = e2(e1 G). The peer's point is validated
+// first to prevent invalid-curve attacks
+func ComputeSharedSecret(priv Key, peerX, peerY *big.Int) ([]byte, error) {
+	if err := checkPrivateKey(priv); err != nil {
+		return nil, err
+	}
+	if err := validatePublicPoint(priv.Curve, peerX, peerY); err != nil {
+		return nil, err
+	}
+
+	x, y := priv.Curve.ScalarMult(peerX, peerY, priv.PrivateBytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("Error: Invalid shared secret, point at infinity")
+	}
+
+	return x.FillBytes(make([]byte, (priv.Curve.Params().BitSize+7)/8)), nil
+}
@@ -0,0 +1,68 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestComputeSharedSecret(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var alice, bob = generateKey(t, curve), generateKey(t, curve)
+
+		aliceSecret, err := ComputeSharedSecret(alice, bob.PublicX, bob.PublicY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobSecret, err := ComputeSharedSecret(bob, alice.PublicX, alice.PublicY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(aliceSecret, bobSecret) {
+			t.Fatalf("%s: secrets differ, %x and %x", curve.Params().Name, aliceSecret, bobSecret)
+		}
+		if len(aliceSecret) != (curve.Params().BitSize+7)/8 {
+			t.Fatalf("%s: secret of %d bytes, want the field size", curve.Params().Name, len(aliceSecret))
+		}
+
+		// The x-coordinate of (e1 e2)G
+		var product = new(big.Int).Mul(alice.Private, bob.Private)
+		x, _ := curve.ScalarBaseMult(product.Mod(product, curve.Params().N).Bytes())
+		if new(big.Int).SetBytes(aliceSecret).Cmp(x) != 0 {
+			t.Fatalf("%s: secret = %x, want %x", curve.Params().Name, aliceSecret, x)
+		}
+
+		var eve = generateKey(t, curve)
+		if eveSecret, _ := ComputeSharedSecret(eve, bob.PublicX, bob.PublicY); bytes.Equal(eveSecret, aliceSecret) {
+			t.Fatalf("%s: a third party derived the same secret", curve.Params().Name)
+		}
+	}
+}
+
+func TestComputeSharedSecretInvalidPeer(t *testing.T) {
+	var curve = nistCurves[1]
+	var key, peer = generateKey(t, curve), generateKey(t, curve)
+
+	var tests = []struct {
+		name         string
+		peerX, peerY *big.Int
+		want         error
+	}{
+		{"off curve", peer.PublicX, new(big.Int).Add(peer.PublicY, big.NewInt(1)), ErrPointNotOnCurve},
+		{"point of P-384", generateKey(t, nistCurves[2]).PublicX, generateKey(t, nistCurves[2]).PublicY, ErrPointNotOnCurve},
+		{"point at infinity", new(big.Int), new(big.Int), ErrIdentityPublicKey},
+		{"missing x", nil, peer.PublicY, nil},
+	}
+
+	for _, test := range tests {
+		secret, err := ComputeSharedSecret(key, test.peerX, test.peerY)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: ComputeSharedSecret = (%x, %v), want %v", test.name, secret, err, test.want)
+		}
+	}
+
+	if _, err := ComputeSharedSecret(Key{Curve: curve}, peer.PublicX, peer.PublicY); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("ComputeSharedSecret without a private key: err = %v, want %v", err, ErrInvalidPrivateKey)
+	}
+}
@@ -1,56 +1,208 @@
 package ecdsaplay
 
 import (
+	"context"
 	"crypto/elliptic"
 	"crypto/rand"
-	"errors"
-	"math"
+	"fmt"
+	"io"
 	"math/big"
 )
 
 // Per-Message secret number generation using extra random bits
 // as described in Federal Information Processing Standard Publication
 // (FIPS PUB 186-4) Digital Signature Standard (DSS) issued July 2013
+//
+// A candidate k outside of 1 <= k <= N-1 is rejected and a fresh one
+// is generated in its place
 func GeneratePreMessageSecret(eC elliptic.Curve) (k *big.Int, err error) {
+	return GeneratePreMessageSecretFrom(rand.Reader, eC)
+}
 
-	// Initializing slice of bytes based on len(n)+64 bits
-	var sliceOfRandomNumbers = make([]byte, (eC.Params().N.BitLen()+64)/8)
+// Number of candidate buffers GeneratePreMessageSecretFrom reads before
+// giving up on a source that keeps failing the health check
+const maxEntropyAttempts = 10
+
+// Per-Message secret number generation as per GeneratePreMessageSecret,
+// reading the random bits from the given source instead of crypto/rand.
+// Any error of the source, including io.EOF or io.ErrUnexpectedEOF once it
+// runs dry, is returned as is. Wrapping the source in a CountingReader shows
+// the bytes consumed
+//
+// As a health check of the source, a buffer of all zero or all one bits,
+// which a working source produces with negligible probability, is discarded.
+// ErrEntropyFailure is returned once maxEntropyAttempts buffers in a row
+// have been discarded
+func GeneratePreMessageSecretFrom(random io.Reader, eC elliptic.Curve) (k *big.Int, err error) {
 
-	// Golang cryptographically secure random number generation
-	_, err = rand.Read(sliceOfRandomNumbers)
+	var one = big.NewInt(int64(1))
+	var nMinusOne = new(big.Int).Sub(eC.Params().N, one)
+
+	for attempt := 0; attempt < maxEntropyAttempts; attempt++ {
+		// Initializing slice of bytes based on len(n)+64 bits, rounded up to
+		// a whole number of bytes, e.g. 74 bytes for the 521-bit order of P-521
+		var returnedBits = eC.Params().N.BitLen() + 64
+		var sliceOfRandomNumbers = make([]byte, (returnedBits+7)/8)
+
+		// Filling the slice completely, as an io.Reader may return fewer
+		// bytes than requested
+		_, err = io.ReadFull(random, sliceOfRandomNumbers)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !healthyEntropy(sliceOfRandomNumbers) {
+			continue
+		}
+
+		// Dropping the bits read beyond len(n)+64
+		c := ConcatenateBytes(sliceOfRandomNumbers)
+		c.Rsh(c, uint(len(sliceOfRandomNumbers)*8-returnedBits))
+
+		k = new(big.Int)
+
+		// Calculating k in accordance with step 6 and 7 of B.5.1 of
+		// Federal Information Processing Standard Publication (FIPS PUB 186-4)
+		// Digital Signature Standard (DSS) issued July 2013
+		k.Mod(c, nMinusOne)
+		k.Add(k, one)
+
+		// 1 <= k <= N-1
+		if IsValidScalar(k, eC) {
+			return k, nil
+		}
+	}
 
-	if err != nil {
-		return nil, err
+	return nil, fmt.Errorf("%w, %d buffers in a row of all zero or all one bits", ErrEntropyFailure, maxEntropyAttempts)
+}
+
+// Whether the random buffer is other than all zero or all one bits
+func healthyEntropy(b []byte) bool {
+	for _, x := range b {
+		if x != b[0] {
+			return true
+		}
+	}
+	return len(b) > 0 && b[0] != 0x00 && b[0] != 0xff
+}
+
+// Private/Public key pair along with its curve.
+//
+// A Key may be used by any number of goroutines at once: Sign, Verify and the
+// other functions of this package only read the values of a Key and of the
+// curve, calculating into freshly allocated or pooled scratch big.Ints. The
+// package's own state is the sync.Once initializing Secp256k1, the sync.Pool
+// of those scratch values, and two mutable package variables read on every
+// call: DebugChecks and nonceSource, the source of k that tests replace.
+// Neither may be changed while other goroutines sign or verify.
+// The exceptions are Zeroize, which overwrites Private and therefore must not
+// run alongside any other use of the key or a copy of it, and changes made
+// by the caller to the big.Ints the Key points to
+type Key struct {
+	Private          *big.Int
+	PublicX, PublicY *big.Int
+	Curve            elliptic.Curve
+}
+
+// Validates the key pair by checking that the private key is within [1, N-1],
+// returning ErrInvalidPrivateKey otherwise, and that the public key equals the
+// private key times G, returning ErrKeyMismatch otherwise
+func (k Key) Validate() error {
+	if err := checkPrivateKey(k); err != nil {
+		return err
 	}
 
-	c := ConcatenateBytes(sliceOfRandomNumbers)
+	x, y := k.Curve.ScalarBaseMult(k.PrivateBytes())
+	if k.PublicX == nil || k.PublicY == nil || x.Cmp(k.PublicX) != 0 || y.Cmp(k.PublicY) != 0 {
+		return ErrKeyMismatch
+	}
 
-	k = new(big.Int)
-	var one = big.NewInt(int64(1))
+	return nil
+}
+
+// Wipes the private scalar by overwriting the words backing it and dropping
+// the reference to it. This is best effort only: the Go runtime or the
+// big.Int arithmetic may have left copies of the value elsewhere in memory
+// that cannot be reached from here. Signing with a zeroized key returns an
+// error
+func (k *Key) Zeroize() {
+	if k.Private == nil {
+		return
+	}
 
-	// Calculating k in accordance with step 6 and 7 of B.5.1 of
-	// Federal Information Processing Standard Publication (FIPS PUB 186-4)
-	// Digital Signature Standard (DSS) issued July 2013
-	k.Mod(c, new(big.Int).Sub(eC.Params().N, one))
-	k.Add(k, big.NewInt(int64(1)))
+	var words = k.Private.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	k.Private.SetInt64(0)
+	k.Private = nil
+}
 
-	if k.Cmp(one) == 1 || k.Cmp(new(big.Int).Sub(eC.Params().N, one)) == -1 {
-		return k, nil
+// Bit size of the underlying field of the key's curve, as given by
+// Params().BitSize, or 0 for a key without a curve
+func (k Key) BitSize() int {
+	if k.Curve == nil {
+		return 0
 	}
+	return k.Curve.Params().BitSize
+}
 
-	return nil, errors.New("Error: Invalid k, outside of the order of group, N")
+// Byte size of a field element, and so of each public key coordinate, of the
+// key's curve, or 0 for a key without a curve
+func (k Key) ByteSize() int {
+	return (k.BitSize() + 7) / 8
+}
 
+// Name of the key's curve when its parameters match one of the curves known
+// to this package, i.e. the NIST curves and secp256k1, or "unknown" otherwise,
+// e.g. for custom curves created by NewCurve
+func (k Key) CurveName() string {
+	if k.Curve == nil {
+		return "unknown"
+	}
+	return knownCurveName(k.Curve)
 }
 
-type Key struct {
-	Private          *big.Int
-	PublicX, PublicY *big.Int
-	Curve            elliptic.Curve
+// Curve name and public point in hex for logging, with the private scalar
+// masked as its byte length so that logging a Key never logs the secret
+func (k Key) String() string {
+	var curveName = "<nil>"
+	if k.Curve != nil {
+		curveName = k.Curve.Params().Name
+	}
+	var private = "<nil>"
+	if k.Private != nil {
+		private = fmt.Sprintf("<masked, %d bytes>", len(k.PrivateBytes()))
+	}
+	return fmt.Sprintf("Key{Curve: %s, PublicX: %x, PublicY: %x, Private: %s}", curveName, k.PublicX, k.PublicY, private)
+}
+
+// Formats the key as per String whatever the verb, so that neither %#v nor
+// e.g. %d, which would otherwise print the fields, shows the private scalar
+func (k Key) Format(f fmt.State, verb rune) {
+	io.WriteString(f, k.String())
+}
+
+// Private scalar as big-endian bytes left-padded with zeros to the byte size
+// of N, e.g. exactly 32 bytes for P-256 and 66 bytes for P-521, unlike
+// Private.Bytes(), which drops leading zero bytes. Returns nil for a key
+// without a private scalar or curve
+func (k Key) PrivateBytes() []byte {
+	if k.Private == nil || k.Curve == nil {
+		return nil
+	}
+	return scalarBytes(k.Private, k.Curve)
 }
 
 // Generates Public/Private key pair in accordance with elliptic curve
-// scalar multiplication
+// scalar multiplication. A nil curve, or one neither among SupportedCurves
+// nor implemented by this package, is rejected with ErrUnsupportedCurve
 func GeneratePrivatePublicKeyPair(eC elliptic.Curve) (key Key, err error) {
+	if !isSupportedCurve(eC) {
+		return Key{}, fmt.Errorf("%w, see SupportedCurves", ErrUnsupportedCurve)
+	}
+
 	key.Curve = eC
 	// Calling Per-Message secret number generation to assign value of k
 	// as private key
@@ -58,99 +210,501 @@ func GeneratePrivatePublicKeyPair(eC elliptic.Curve) (key Key, err error) {
 	if err != nil {
 		return key, err
 	}
+	if err = checkPrivateKey(key); err != nil {
+		return key, err
+	}
 
-	key.PublicX, key.PublicY = eC.ScalarBaseMult(key.Private.Bytes())
+	key.PublicX, key.PublicY = eC.ScalarBaseMult(key.PrivateBytes())
 	return key, nil
 
 }
 
+// Key pair for an existing private scalar d, e.g. one imported from another
+// system, with the public key calculated as dG. d must be within [1, N-1]
+// and is copied, so later changes to d do not affect the key
+func NewKeyFromScalar(curve elliptic.Curve, d *big.Int) (Key, error) {
+	var key = Key{Curve: curve}
+	if d != nil {
+		key.Private = new(big.Int).Set(d)
+	}
+	if err := checkPrivateKey(key); err != nil {
+		return Key{}, err
+	}
+
+	key.PublicX, key.PublicY = curve.ScalarBaseMult(key.PrivateBytes())
+	return key, nil
+}
+
+// Key pair for a private scalar given as big-endian bytes, as per
+// NewKeyFromScalar
+func NewKeyFromBytes(curve elliptic.Curve, d []byte) (Key, error) {
+	return NewKeyFromScalar(curve, new(big.Int).SetBytes(d))
+}
+
+// Number of values of k tried by Sign before giving up. r = 0 or s = 0 occurs
+// with negligible probability for a working random number generator, so
+// reaching this bound indicates a broken one
+const maxSignAttempts = 100
+
+// Source of the randomness of k for Sign, SignContext and SignFull. Only
+// tests replace it, e.g. with a reader returning the same bytes to two calls
+// of Sign so that both use the same k, reproducing the nonce reuse that
+// RecoverPrivateFromReusedNonce exploits. It must remain rand.Reader
+// everywhere else
+var nonceSource io.Reader = rand.Reader
+
 // Signature = (r, s); where, r is the x-coordinate of the R which is calculated as kG
 // and k itself is selected randomly and s = (z + re)/k; where, z is hash of the message
-// to be signed and e = private key
+// to be signed and e = private key. The hash must be non-empty, or ErrInvalidHashLength
+// is returned, and at most MaxMessageHashSize bytes long, or the byte size of N if that
+// is larger, or ErrHashTooLong is returned. Within that bound any digest is accepted,
+// e.g. SHA-384 with P-384 or SHA-512 with P-521, as HashToInt truncates it to the bit
+// length of N
 func Sign(key Key, messageHash []byte) (r, s *big.Int, err error) {
-	privateKey := key.Private
+	return SignContext(context.Background(), key, messageHash)
+}
+
+// Signature as per Sign, that stops selecting fresh values of k and returns
+// ctx.Err() once ctx is cancelled or its deadline is exceeded. The message
+// hash is bounded as in Sign: an empty one fails with ErrInvalidHashLength,
+// and one longer than MaxMessageHashSize, or than the byte size of N if that
+// is larger, with ErrHashTooLong
+func SignContext(ctx context.Context, key Key, messageHash []byte) (r, s *big.Int, err error) {
+	r, s, _, _, err = signPoint(ctx, nonceSource, key, messageHash)
+	return r, s, err
+}
+
+// Signature as per Sign, that also returns the complete point R = kG, of
+// which r is the x-coordinate mod N. This lets callers determine the
+// recovery id from the parity of Ry and whether Rx >= N
+func SignFull(key Key, messageHash []byte) (r, s, Rx, Ry *big.Int, err error) {
+	return signPoint(context.Background(), nonceSource, key, messageHash)
+}
+
+// Signature as per SignContext along with the point R = kG, drawing k from
+// the given source of randomness
+func signPoint(ctx context.Context, random io.Reader, key Key, messageHash []byte) (r, s, Rx, Ry *big.Int, err error) {
+	if err = checkPrivateKey(key); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err = checkSigningHashLength(key.Curve, messageHash); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	var randomK *big.Int
-	r = new(big.Int)
-	var re = new(big.Int)
-	s = new(big.Int)
 
-	// Calling Per-Message secret number generation to assign value of k
-	// as a random number
-	randomK, err = GeneratePreMessageSecret(key.Curve)
+	// A fresh k is selected whenever r = 0 or s = 0
+	for attempt := 0; attempt < maxSignAttempts; attempt++ {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, nil, nil, err
+		}
 
-	if err != nil {
-		return nil, nil, err
+		// Calling Per-Message secret number generation to assign value of k
+		// as a random number
+		randomK, err = GeneratePreMessageSecretFrom(random, key.Curve)
+
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		r, s, Rx, Ry = signWithKPoint(key, messageHash, randomK)
+		if r.Sign() != 0 && s.Sign() != 0 {
+			return r, s, Rx, Ry, nil
+		}
 	}
 
-	// r = kG (x-coordinate only)
-	r, _ = key.Curve.ScalarBaseMult(randomK.Bytes())
+	return nil, nil, nil, nil, fmt.Errorf("%w, no valid k after %d attempts", ErrZeroSignature, maxSignAttempts)
+}
 
-	// s = (z + re)
-	s = s.Add(ConcatenateBytes(messageHash), re.Mul(privateKey, r))
+// Signature as per Sign, using the caller supplied per-message secret k
+// instead of calling GeneratePreMessageSecret. This allows known answer
+// tests to be reproduced exactly. k must be within [1, N-1], and since it
+// cannot be replaced, a k leading to r = 0 or s = 0 is an error
+func SignWithK(key Key, messageHash []byte, k *big.Int) (r, s *big.Int, err error) {
+	if err = checkPrivateKey(key); err != nil {
+		return nil, nil, err
+	}
+	if !IsValidScalar(k, key.Curve) {
+		return nil, nil, fmt.Errorf("%w, outside of the order of group, N", ErrInvalidK)
+	}
+	if err = checkSigningHashLength(key.Curve, messageHash); err != nil {
+		return nil, nil, err
+	}
 
-	var invK = inverse(randomK, key.Curve.Params().N)
-	s = s.Mul(s, invK)
-	s = s.Mod(s, key.Curve.Params().N)
+	r, s = signWithK(key, messageHash, k)
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return nil, nil, fmt.Errorf("%w, k must be replaced", ErrZeroSignature)
+	}
 
 	return r, s, nil
 }
 
+// Intermediate values of the signing equation s = (z + re)/k mod N
+type SignatureComponents struct {
+	// Message hash as an integer, truncated to the bit length of N
+	Z *big.Int
+
+	// x-coordinate of kG mod N
+	R *big.Int
+
+	// r times the private key e mod N
+	RE *big.Int
+
+	// Inverse of k mod N
+	KInv *big.Int
+
+	// (z + re) * k^-1 mod N
+	S *big.Int
+}
+
+// Signature as per SignWithK, returning every intermediate value of the
+// signing equation along with r and s so that each step can be inspected
+func SignComponents(key Key, messageHash []byte, k *big.Int) (SignatureComponents, error) {
+	r, s, err := SignWithK(key, messageHash, k)
+	if err != nil {
+		return SignatureComponents{}, err
+	}
+
+	return SignatureComponents{
+		Z:    HashToInt(messageHash, key.Curve),
+		R:    r,
+		RE:   modN(new(big.Int).Mul(r, key.Private), key.Curve),
+		KInv: inverse(k, key.Curve.Params().N),
+		S:    s,
+	}, nil
+}
+
+// Rejects a key whose private scalar is missing, has been zeroized, or is
+// otherwise outside of [1, N-1], e.g. negative, with ErrInvalidPrivateKey
+func checkPrivateKey(key Key) error {
+	if key.Private == nil || key.Private.Sign() == 0 {
+		return fmt.Errorf("%w, missing or zeroized", ErrInvalidPrivateKey)
+	}
+	if key.Curve == nil {
+		return fmt.Errorf("%w, missing curve", ErrInvalidPrivateKey)
+	}
+	if !IsValidScalar(key.Private, key.Curve) {
+		return fmt.Errorf("%w, outside of the order of group, N", ErrInvalidPrivateKey)
+	}
+	return nil
+}
+
+// Reports whether x is within [1, N-1] for the order N of the curve, i.e. a
+// valid private key, per-message secret k, or r or s of a signature. A nil x
+// or curve is not
+func IsValidScalar(x *big.Int, curve elliptic.Curve) bool {
+	if x == nil || curve == nil {
+		return false
+	}
+	return x.Sign() > 0 && x.Cmp(curve.Params().N) < 0
+}
+
+// Computes r = kG (x-coordinate only) mod N and s = (z + re)/k mod N for
+// the given per-message secret k. A zero r or s is returned as is so that
+// the caller can select a fresh k
+func signWithK(key Key, messageHash []byte, k *big.Int) (r, s *big.Int) {
+	r, s, _, _ = signWithKPoint(key, messageHash, k)
+	return r, s
+}
+
+// Signature as per signWithK along with the point R = kG
+func signWithKPoint(key Key, messageHash []byte, k *big.Int) (r, s, Rx, Ry *big.Int) {
+	// r = kG (x-coordinate only) mod N
+	Rx, Ry = key.Curve.ScalarBaseMult(scalarBytes(k, key.Curve))
+	r = modN(Rx, key.Curve)
+
+	if r.Sign() == 0 {
+		return r, new(big.Int), Rx, Ry
+	}
+
+	// re = (r * e) mod N
+	var re = getScratch()
+	defer putScratch(re)
+	modNTo(re, re.Mul(r, key.Private), key.Curve)
+
+	// s = (z + re) mod N
+	var sum = getScratch()
+	defer putScratch(sum)
+	modNTo(sum, sum.Add(HashToInt(messageHash, key.Curve), re), key.Curve)
+
+	// s = (z + re)/k mod N
+	var invK = inverseTo(getScratch(), k, key.Curve.Params().N)
+	defer putScratch(invK)
+	s = modN(sum.Mul(sum, invK), key.Curve)
+
+	return r, s, Rx, Ry
+}
+
 // Verification is based on validation of r.
 // u = z/s and v = r/s are calculated
 // Signature is valid if x-axis of r calculated from uG + vP = R
 // is equal to the r included in signature
 func Verify(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte) bool {
-	z := ConcatenateBytes(messageHash)
+	valid, _ := VerifyDetailed(r, s, publicKeyX, publicKeyY, curve, messageHash)
+	return valid
+}
+
+// Verification as per VerifyV2, given the message hash as the integer z
+// produced by HashToInt instead of the hash itself, so that a message
+// verified against many public keys is converted only once
+func VerifyPrecomputed(z *big.Int, sig Signature, pub PublicKey) bool {
+	if z == nil || pub.Curve == nil {
+		return false
+	}
+	valid, _ := verifyZ(sig.R, sig.S, nil, pub.X, pub.Y, pub.Curve, z, scalarMultCombination)
+	return valid
+}
+
+// Verification as per Verify, that also returns the reason a signature is
+// rejected: ErrROutOfRange or ErrSOutOfRange when r or s is outside of
+// [1, N-1], ErrIdentityPublicKey when the public key is the point at
+// infinity (0, 0), another error describing an invalid public key,
+// ErrInfinityPoint when uG + vP is the point at infinity,
+// ErrRecomputedRMismatch when the calculated r differs from the one included
+// in the signature, or ErrHashTooLong when the message hash is too long to be
+// a digest
+func VerifyDetailed(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte) (bool, error) {
+	return verify(r, s, nil, publicKeyX, publicKeyY, curve, messageHash, scalarMultCombination)
+}
+
+// Calculates uG + vP, returning (0, 0) for the point at infinity
+type linearCombination func(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int)
+
+// Verification as per VerifyDetailed, using invS as s^-1 mod N when it has
+// already been calculated (e.g. by BatchInverse) and calculating it otherwise,
+// and using combine to calculate uG + vP
+func verify(r, s, invS, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte, combine linearCombination) (bool, error) {
+	if err := checkHashLength(curve, messageHash); err != nil {
+		return false, err
+	}
+	return verifyZ(r, s, invS, publicKeyX, publicKeyY, curve, HashToInt(messageHash, curve), combine)
+}
 
-	var u = new(big.Int)
-	var v = new(big.Int)
+// Verification as per verify, given the message hash as the integer z
+func verifyZ(r, s, invS, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, z *big.Int, combine linearCombination) (bool, error) {
+	// r and s must both be within [1, N-1]; notably s = 0 has no inverse
+	if !IsValidScalar(r, curve) {
+		return false, ErrROutOfRange
+	}
+	if !IsValidScalar(s, curve) {
+		return false, ErrSOutOfRange
+	}
+
+	// The public key must be a point on the curve other than infinity
+	if err := validatePublicPoint(curve, publicKeyX, publicKeyY); err != nil {
+		return false, err
+	}
 
-	var invS = inverse(s, curve.Params().N)
+	if invS == nil {
+		invS = inverseTo(getScratch(), s, curve.Params().N)
+		defer putScratch(invS)
+	} else {
+		// Supplied by the caller, e.g. from BatchInverse
+		checkInverse(s, invS, curve.Params().N)
+	}
 
 	// u = z/s and v = r/s
-	u = u.Mul(z, invS)
-	u = u.Mod(u, curve.Params().N)
-	v = v.Mul(r, invS)
-	v = v.Mod(v, curve.Params().N)
+	var u = getScratch()
+	defer putScratch(u)
+	modNTo(u, u.Mul(z, invS), curve)
+	var v = getScratch()
+	defer putScratch(v)
+	modNTo(v, v.Mul(r, invS), curve)
+
+	// r = uG + vP (x-coordinate only) mod N
+	calRx, calRy := combine(curve, u, v, publicKeyX, publicKeyY)
+
+	// crypto/elliptic represents the point at infinity as (0, 0)
+	if calRx.Sign() == 0 && calRy.Sign() == 0 {
+		return false, ErrInfinityPoint
+	}
+
+	calRx = modN(calRx, curve)
 
+	// fmt.Println("Signature r = ", r)
+	// fmt.Println("Calculated r = ", calRx)
+
+	if calRx.Cmp(r) != 0 {
+		return false, ErrRecomputedRMismatch
+	}
+	return true, nil
+}
+
+// uG + vP by two separate scalar multiplications followed by an addition
+func scalarMultCombination(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
 	// uG and vP
 	var uGx, uGy *big.Int
 	var vPx, vPy *big.Int
 	uGx, uGy = curve.ScalarBaseMult(u.Bytes())
 	vPx, vPy = curve.ScalarMult(publicKeyX, publicKeyY, v.Bytes())
 
-	// r = uG + vP (x-coordinate only)
-	calRx, _ := curve.Add(uGx, uGy, vPx, vPy)
+	return addPoints(curve, uGx, uGy, vPx, vPy)
+}
+
+// (x1, y1) + (x2, y2), returning (0, 0) for the point at infinity
+func addPoints(curve elliptic.Curve, x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	// The sum is the point at infinity when (x2, y2) = -(x1, y1), i.e. both
+	// share the x-coordinate and their y-coordinates add up to 0 mod p. This
+	// is detected before the addition so as not to rely on every curve's Add
+	// handling it
+	var negY1 = new(big.Int).Neg(y1)
+	negY1.Mod(negY1, curve.Params().P)
+	if x1.Cmp(x2) == 0 && negY1.Cmp(y2) == 0 {
+		return new(big.Int), new(big.Int)
+	}
 
-	// fmt.Println("Signature r = ", r)
-	// fmt.Println("Calculated r = ", calRx)
+	return curve.Add(x1, y1, x2, y2)
+}
 
-	return calRx.Cmp(r) == 0
+// Both (r, s) and (r, N-s) are valid signatures for the same message. Low-s
+// normalization picks the one with s <= N/2 so that a signature cannot be
+// altered by a third party into another valid one (malleability)
+func NormalizeS(s *big.Int, curve elliptic.Curve) *big.Int {
+	var n = curve.Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		return new(big.Int).Sub(n, s)
+	}
+	return new(big.Int).Set(s)
 }
 
-// Converts byte(s) stored in slice of data as a single concatenated big Int value
+// Verification as per Verify, that additionally rejects signatures whose s
+// is in the upper half of [1, N-1], i.e. that are not low-s normalized
+func VerifyStrict(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte) bool {
+	if s == nil || s.Cmp(new(big.Int).Rsh(curve.Params().N, 1)) > 0 {
+		return false
+	}
+	return Verify(r, s, publicKeyX, publicKeyY, curve, messageHash)
+}
+
+// Converts byte(s) stored in slice of data as a single big Int value by
+// interpreting the slice as a big-endian, base-256 unsigned integer,
+// e.g. {0x01, 0x00} = 256
 func ConcatenateBytes(bytes []byte) *big.Int {
-	// Initializing non-negative random integer c as golang big.Int
-	var c = new(big.Int)
+	return new(big.Int).SetBytes(bytes)
+}
 
-	for i := len(bytes) - 1; i >= 0; i-- {
+// Encodes a secret scalar, i.e. a private key or k, left-padded with zeros to
+// the byte size of N. Passing k.Bytes() to ScalarMult or ScalarBaseMult would
+// make the number of doublings, and hence the time taken, depend on the
+// number of leading zero bytes of the scalar. Scalars too large to fit, which
+// are invalid anyway, are encoded as is rather than panicking.
+//
+// This does not make signing constant-time: math/big is variable-time
+// throughout, the extended Euclidean inverse of k depends on its value and
+// the double-and-add of secp256k1 only adds for set bits. Only the scalar
+// multiplications on the NIST curves, which crypto/elliptic hands to
+// constant-time implementations, are free of such leaks. Treat this package
+// as unsuitable wherever an attacker can measure signing times
+func scalarBytes(k *big.Int, curve elliptic.Curve) []byte {
+	var byteLen = scalarByteLen(curve)
+	if (k.BitLen()+7)/8 > byteLen {
+		return k.Bytes()
+	}
+	return k.FillBytes(make([]byte, byteLen))
+}
 
-		// Iterating through slice of random bytes as golang big.Int
-		var randomByte = big.NewInt(int64(bytes[i]))
+// Longest message hash accepted, in bytes, by signing and verification on
+// curves whose N is at most 512 bits: that of SHA-512, the longest digest in
+// common use. Curves with a larger N, such as P-521, accept message hashes
+// up to the byte size of N instead
+const MaxMessageHashSize = 64
+
+// Rejects a message hash longer than MaxMessageHashSize, or than the byte
+// size of N if that is larger, with ErrHashTooLong. Such input is not a
+// digest, e.g. a message passed where its hash was expected
+func checkHashLength(curve elliptic.Curve, messageHash []byte) error {
+	var maxLen = MaxMessageHashSize
+	if byteLen := (curve.Params().N.BitLen() + 7) / 8; byteLen > maxLen {
+		maxLen = byteLen
+	}
+	if len(messageHash) > maxLen {
+		return fmt.Errorf("%w, %d bytes exceeds %d", ErrHashTooLong, len(messageHash), maxLen)
+	}
+	return nil
+}
 
-		// Dynamic offset used to help concatenate all elements as a single big.Int value
-		var offset = new(big.Int)
-		offset.Exp(big.NewInt(int64(1000)), big.NewInt(int64(int(math.Abs(float64(i-len(bytes))+1)))), big.NewInt(int64(0)))
+// Rejects a message hash as per checkHashLength, and an empty one with
+// ErrInvalidHashLength, for every way of signing. Verification only applies
+// checkHashLength, so that an empty hash fails on the recomputed r instead
+func checkSigningHashLength(curve elliptic.Curve, messageHash []byte) error {
+	if len(messageHash) == 0 {
+		return fmt.Errorf("%w, empty", ErrInvalidHashLength)
+	}
+	return checkHashLength(curve, messageHash)
+}
 
-		// non-negative random integer c being concatenated from elements of slice
-		c.Add(c, new(big.Int).Mul(randomByte, offset))
+// Converts a message hash to the integer z by taking its leftmost N.BitLen()
+// bits, as described in section 6.4 of Federal Information Processing
+// Standard Publication (FIPS PUB 186-4) Digital Signature Standard (DSS)
+// issued July 2013. Hashes no longer than the order of the group are used
+// as is
+func HashToInt(messageHash []byte, curve elliptic.Curve) *big.Int {
+	var orderBits = curve.Params().N.BitLen()
+	var orderBytes = (orderBits + 7) / 8
+	if len(messageHash) > orderBytes {
+		messageHash = messageHash[:orderBytes]
 	}
-	return c
+
+	var z = ConcatenateBytes(messageHash)
+	var excess = len(messageHash)*8 - orderBits
+	if excess > 0 {
+		z.Rsh(z, uint(excess))
+	}
+	return z
 }
 
-// Calculates inverse in accordance with Fermat Little theorm
-// d^-1 = d^(prime-2); where d is denominator to be inversed
+// x mod N, as a new value within [0, N-1] for any x including negative ones,
+// since big.Int.Mod implements Euclidean modulus. Every reduction of the
+// signing and verification equations goes through here so that none is
+// missed or made with the wrong modulus
+func modN(x *big.Int, curve elliptic.Curve) *big.Int {
+	return modNTo(new(big.Int), x, curve)
+}
+
+// x mod N as per modN, stored in and returning dst, which may be x itself
+func modNTo(dst, x *big.Int, curve elliptic.Curve) *big.Int {
+	return dst.Mod(x, curve.Params().N)
+}
+
+// Enables internal self-checks that panic when an invariant of the
+// arithmetic is broken, e.g. an inverse d^-1 for which d*d^-1 mod N is not 1,
+// as a broken modulus or a faulty refactoring of inverse would produce. The
+// checks cost an extra multiplication each and are off by default. Set it
+// before signing or verifying rather than while other goroutines do so
+var DebugChecks = false
+
+// Calculates inverse using the extended Euclidean algorithm
+// d^-1; where d is denominator to be inversed and d*d^-1 = 1 mod prime.
+// A d without an inverse (e.g. d = 0 mod prime) yields the sentinel 0,
+// which is never a valid inverse
 func inverse(d *big.Int, prime *big.Int) *big.Int {
+	return inverseTo(new(big.Int), d, prime)
+}
+
+// d^-1 mod prime as per inverse, stored in and returning dst
+func inverseTo(dst, d, prime *big.Int) *big.Int {
+	if dst.ModInverse(d, prime) == nil {
+		return dst.SetInt64(0)
+	}
+	checkInverse(d, dst, prime)
+	return dst
+}
+
+// Panics if DebugChecks is set and d*inv mod modulus is not 1
+func checkInverse(d, inv, modulus *big.Int) {
+	if !DebugChecks {
+		return
+	}
+	var product = new(big.Int).Mul(d, inv)
+	if product.Mod(product, modulus).Cmp(big.NewInt(1)) != 0 {
+		panic(fmt.Sprintf("ecdsaplay: %x * %x mod %x is not 1", d, inv, modulus))
+	}
+}
+
+// Calculates inverse in accordance with Fermat Little theorm
+// d^-1 = d^(prime-2); where d is denominator to be inversed.
+// Retained as a reference for comparison with inverse by BenchmarkInverse
+func inverseFermat(d *big.Int, prime *big.Int) *big.Int {
 	var invResult = new(big.Int)
 	var exponent = new(big.Int)
 	exponent = exponent.Sub(prime, big.NewInt(2))
@@ -0,0 +1,1677 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Curves of crypto/elliptic, each exercised by the tests
+var nistCurves = []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()}
+
+// SHA-256 of the message used throughout main.go
+var testMessageHash = sha256.Sum256([]byte("Take the red pill!"))
+
+// Fresh key pair on the curve, failing the test on error
+func generateKey(t testing.TB, curve elliptic.Curve) Key {
+	t.Helper()
+	key, err := GeneratePrivatePublicKeyPair(curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// Curve y^2 = x^3 - 3x + 27 over the 16 bit prime 32831, of prime order
+// 32999 and with G = (1, 5), small enough for tests to search for scalars
+// that are vanishingly rare on real curves. As N is just above 2^15, half of
+// all 16 bit candidates for a scalar fall outside of [1, N-1]
+func toyCurve(t testing.TB) elliptic.Curve {
+	t.Helper()
+	curve, err := NewCurve("toy", big.NewInt(32831), big.NewInt(-3), big.NewInt(27), big.NewInt(1), big.NewInt(5), big.NewInt(32999), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return curve
+}
+
+// Integer of the hexadecimal string, which must be valid
+func hexInt(s string) *big.Int {
+	var x, ok = new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex " + s)
+	}
+	return x
+}
+
+// Bytes of the hexadecimal string, which must be valid
+func hexBytes(s string) []byte {
+	var b, err = hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Replaces the source of k used by Sign for the duration of the test. Tests
+// doing so must not run in parallel
+func setNonceSource(t *testing.T, random io.Reader) {
+	t.Helper()
+	var original = nonceSource
+	nonceSource = random
+	t.Cleanup(func() { nonceSource = original })
+}
+
+// Sets DebugChecks for the duration of the test, as per setNonceSource
+func setDebugChecks(t *testing.T, on bool) {
+	t.Helper()
+	var original = DebugChecks
+	DebugChecks = on
+	t.Cleanup(func() { DebugChecks = original })
+}
+
+// Random buffer that GeneratePreMessageSecret reads as the candidate c, i.e.
+// c shifted into the leftmost N.BitLen()+64 bits of the buffer
+func candidateBuffer(curve elliptic.Curve, c *big.Int) []byte {
+	var returnedBits = curve.Params().N.BitLen() + 64
+	var byteLen = (returnedBits + 7) / 8
+	var shifted = new(big.Int).Lsh(c, uint(byteLen*8-returnedBits))
+	return shifted.FillBytes(make([]byte, byteLen))
+}
+
+func TestConcatenateBytes(t *testing.T) {
+	var tests = []struct {
+		bytes []byte
+		want  int64
+	}{
+		{nil, 0},
+		{[]byte{0x00}, 0},
+		{[]byte{0x2a}, 42},
+		{[]byte{0x01, 0x00}, 256},
+		{[]byte{0x00, 0x01}, 1},
+		{[]byte{0x12, 0x34, 0x56}, 0x123456},
+		{[]byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1<<63 - 1},
+	}
+
+	for _, test := range tests {
+		if got := ConcatenateBytes(test.bytes); got.Cmp(big.NewInt(test.want)) != 0 {
+			t.Errorf("ConcatenateBytes(%x) = %v, want %v", test.bytes, got, test.want)
+		}
+	}
+}
+
+func TestConcatenateBytesRoundTrip(t *testing.T) {
+	var value, _ = new(big.Int).SetString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721", 16)
+	var b = value.FillBytes(make([]byte, 32))
+
+	if got := ConcatenateBytes(b); got.Cmp(value) != 0 {
+		t.Fatalf("ConcatenateBytes(%x) = %x, want %x", b, got, value)
+	}
+	if got := ConcatenateBytes(b).FillBytes(make([]byte, 32)); !bytes.Equal(got, b) {
+		t.Fatalf("round trip of %x gave %x", b, got)
+	}
+}
+
+func TestGeneratePreMessageSecretFromRange(t *testing.T) {
+	for _, curve := range nistCurves {
+		var n = curve.Params().N
+		var nMinusOne = new(big.Int).Sub(n, big.NewInt(1))
+
+		// Candidates at the edges of k = (c mod (N-1)) + 1, including c = N,
+		// which a plain c mod N would turn into k = 0
+		var tests = []struct {
+			name string
+			c    *big.Int
+			want *big.Int
+		}{
+			{"c = N-2", new(big.Int).Sub(n, big.NewInt(2)), nMinusOne},
+			{"c = N-1", nMinusOne, big.NewInt(1)},
+			{"c = N", new(big.Int).Set(n), big.NewInt(2)},
+			{"c = 2(N-1)", new(big.Int).Lsh(nMinusOne, 1), big.NewInt(1)},
+		}
+
+		for _, test := range tests {
+			k, err := GeneratePreMessageSecretFrom(bytes.NewReader(candidateBuffer(curve, test.c)), curve)
+			if err != nil {
+				t.Fatalf("%s %s: %v", curve.Params().Name, test.name, err)
+			}
+			if k.Cmp(test.want) != 0 || !IsValidScalar(k, curve) {
+				t.Errorf("%s %s: k = %x, want %x", curve.Params().Name, test.name, k, test.want)
+			}
+		}
+	}
+}
+
+func TestGeneratePreMessageSecretFromRetries(t *testing.T) {
+	var curve = elliptic.P256()
+	var byteLen = len(candidateBuffer(curve, big.NewInt(0)))
+
+	// An all zero buffer, as a stuck source emits and which gives c = 0, is
+	// discarded, as is an all one buffer, and the next candidate is used
+	var stubbed bytes.Buffer
+	stubbed.Write(make([]byte, byteLen))
+	stubbed.Write(bytes.Repeat([]byte{0xff}, byteLen))
+	stubbed.Write(candidateBuffer(curve, big.NewInt(41)))
+
+	k, err := GeneratePreMessageSecretFrom(&stubbed, curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("k = %v, want 42 from the third buffer", k)
+	}
+}
+
+// Source failing with the given error on every read
+type failingReader struct {
+	err error
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	return 0, f.err
+}
+
+func TestGeneratePreMessageSecretFromReaderErrors(t *testing.T) {
+	var curve = elliptic.P256()
+	var buffer = candidateBuffer(curve, big.NewInt(41))
+	var broken = errors.New("broken source")
+
+	var tests = []struct {
+		name   string
+		random io.Reader
+		want   error
+	}{
+		{"empty", bytes.NewReader(nil), io.EOF},
+		{"truncated buffer", bytes.NewReader(buffer[:len(buffer)-1]), io.ErrUnexpectedEOF},
+		{"failing source", failingReader{broken}, broken},
+	}
+
+	for _, test := range tests {
+		if k, err := GeneratePreMessageSecretFrom(test.random, curve); err != test.want {
+			t.Errorf("%s: GeneratePreMessageSecretFrom = (%v, %v), want %v", test.name, k, err, test.want)
+		}
+	}
+
+	// Sign reading nonceSource passes the error of the source through
+	setNonceSource(t, bytes.NewReader(nil))
+	if r, s, err := Sign(generateKey(t, curve), testMessageHash[:]); !errors.Is(err, io.EOF) {
+		t.Errorf("Sign with an exhausted source = (%v, %v, %v), want io.EOF", r, s, err)
+	}
+}
+
+func TestHealthyEntropy(t *testing.T) {
+	var tests = []struct {
+		b    []byte
+		want bool
+	}{
+		{nil, false},
+		{[]byte{0x00}, false},
+		{[]byte{0xff}, false},
+		{make([]byte, 40), false},
+		{bytes.Repeat([]byte{0xff}, 40), false},
+		{[]byte{0x01}, true},
+		{bytes.Repeat([]byte{0x55}, 40), true},
+		{append(make([]byte, 39), 0x01), true},
+		{append(bytes.Repeat([]byte{0xff}, 39), 0xfe), true},
+	}
+
+	for _, test := range tests {
+		if got := healthyEntropy(test.b); got != test.want {
+			t.Errorf("healthyEntropy(%x) = %v, want %v", test.b, got, test.want)
+		}
+	}
+}
+
+func TestGeneratePreMessageSecretFromBrokenSource(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var byteLen = len(candidateBuffer(curve, big.NewInt(0)))
+		var zeros, ones = make([]byte, byteLen), bytes.Repeat([]byte{0xff}, byteLen)
+
+		// Reads of all zero or all one bits, then a healthy candidate 41
+		var source = func(bad int, stuck []byte) io.Reader {
+			var b bytes.Buffer
+			for i := 0; i < bad; i++ {
+				b.Write(stuck)
+			}
+			b.Write(candidateBuffer(curve, big.NewInt(41)))
+			return &b
+		}
+
+		var tests = []struct {
+			name   string
+			random io.Reader
+			ok     bool
+		}{
+			{"zero-filled reader", bytes.NewReader(make([]byte, 1<<16)), false},
+			{"one-filled reader", bytes.NewReader(bytes.Repeat([]byte{0xff}, 1<<16)), false},
+			{"zero buffers up to the limit", source(maxEntropyAttempts, zeros), false},
+			{"zero buffers below the limit", source(maxEntropyAttempts-1, zeros), true},
+			{"one buffers below the limit", source(maxEntropyAttempts-1, ones), true},
+		}
+
+		for _, test := range tests {
+			k, err := GeneratePreMessageSecretFrom(test.random, curve)
+			if !test.ok {
+				if !errors.Is(err, ErrEntropyFailure) {
+					t.Errorf("%s %s: GeneratePreMessageSecretFrom = (%v, %v), want %v", curve.Params().Name, test.name, k, err, ErrEntropyFailure)
+				}
+				continue
+			}
+			if err != nil || k.Cmp(big.NewInt(42)) != 0 {
+				t.Errorf("%s %s: GeneratePreMessageSecretFrom = (%v, %v), want 42", curve.Params().Name, test.name, k, err)
+			}
+		}
+	}
+
+	// Signing with a zero-filled nonceSource fails rather than using k = 1
+	setNonceSource(t, bytes.NewReader(make([]byte, 1<<16)))
+	if r, s, err := Sign(generateKey(t, elliptic.P256()), testMessageHash[:]); !errors.Is(err, ErrEntropyFailure) {
+		t.Errorf("Sign with a zero-filled source = (%v, %v, %v), want %v", r, s, err, ErrEntropyFailure)
+	}
+}
+
+func TestGeneratePreMessageSecret(t *testing.T) {
+	for _, curve := range nistCurves {
+		for i := 0; i < 100; i++ {
+			k, err := GeneratePreMessageSecret(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsValidScalar(k, curve) {
+				t.Fatalf("%s: k = %x outside of [1, N-1]", curve.Params().Name, k)
+			}
+		}
+	}
+}
+
+func TestSignRRange(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		for i := 0; i < 200; i++ {
+			r, s, Rx, _, err := SignFull(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsValidScalar(r, curve) || !IsValidScalar(s, curve) {
+				t.Fatalf("%s: r = %x, s = %x outside of [1, N-1]", curve.Params().Name, r, s)
+			}
+			if r.Cmp(new(big.Int).Mod(Rx, curve.Params().N)) != 0 {
+				t.Fatalf("%s: r = %x, want Rx mod N for Rx = %x", curve.Params().Name, r, Rx)
+			}
+		}
+	}
+}
+
+func TestSignSeededKnownAnswer(t *testing.T) {
+	// First P-256, SHA-256 case of the SigGen vectors of FIPS 186-3 CAVP
+	var curve = elliptic.P256()
+	var message = hexBytes("5905238877c77421f73e43ee3da6f2d9e2ccad5fc942dcec0cbd25482935faaf416983fe165b1a045ee2bcd2e6dca3bdf46c4310a7461f9a37960ca672d3feb5473e253605fb1ddfd28065b53cb5858a8ad28175bf9bd386a5e471ea7a65c17cc934a9d791e91491eb3754d03799790fe2d308d16146d5c9b0d0debd97d79ce8")
+	var d = hexInt("519b423d715f8b581f4fa8ee59f4771a5b44c8130b4e3eacca54a56dda72b464")
+	var k = hexInt("94a1bbb14b906a61a280f245f9e93c7f3b4a6247824f5d33b9670787642a68de")
+	var wantR = hexInt("f3ac8061b514795b8843e3d6629527ed2afd6b1f6a555a7acabb5e6f79c8c2ac")
+	var wantS = hexInt("8bf77819ca05a6b2786c76262bf7371cef97b218e96f175a3ccdda2acc058903")
+
+	var key = Key{Private: d, Curve: curve}
+	key.PublicX, key.PublicY = curve.ScalarBaseMult(d.Bytes())
+	var messageHash = sha256.Sum256(message)
+
+	// A source seeded so that GeneratePreMessageSecret draws exactly k
+	setNonceSource(t, bytes.NewReader(candidateBuffer(curve, new(big.Int).Sub(k, big.NewInt(1)))))
+	r, s, err := Sign(key, messageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		t.Fatalf("(r, s) = (%x, %x), want (%x, %x)", r, s, wantR, wantS)
+	}
+
+	// s = k^-1 (z + rd) mod N, calculated step by step by hand
+	var n = curve.Params().N
+	var handS = new(big.Int).Mul(wantR, d)
+	handS.Add(handS, new(big.Int).SetBytes(messageHash[:]))
+	handS.Mul(handS, new(big.Int).ModInverse(k, n))
+	handS.Mod(handS, n)
+	if s.Cmp(handS) != 0 {
+		t.Fatalf("s = %x, want %x by hand", s, handS)
+	}
+}
+
+func TestVerifyRange(t *testing.T) {
+	for _, curve := range nistCurves {
+		var key = generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var n = curve.Params().N
+		// Values in range still fail, but on the recomputed r rather than
+		// the range check
+		var tests = []struct {
+			name    string
+			value   *big.Int
+			inRange bool
+		}{
+			{"0", big.NewInt(0), false},
+			{"1", big.NewInt(1), true},
+			{"N-1", new(big.Int).Sub(n, big.NewInt(1)), true},
+			{"N", new(big.Int).Set(n), false},
+			{"N+1", new(big.Int).Add(n, big.NewInt(1)), false},
+			{"-1", big.NewInt(-1), false},
+			{"-N", new(big.Int).Neg(n), false},
+			{"nil", nil, false},
+		}
+
+		for _, test := range tests {
+			var wantR, wantS = ErrROutOfRange, ErrSOutOfRange
+			if test.inRange {
+				wantR, wantS = ErrRecomputedRMismatch, ErrRecomputedRMismatch
+			}
+
+			if Verify(test.value, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s: Verify accepted r = %s", curve.Params().Name, test.name)
+			}
+			if _, err := VerifyDetailed(test.value, s, key.PublicX, key.PublicY, curve, testMessageHash[:]); !errors.Is(err, wantR) {
+				t.Errorf("%s: r = %s: err = %v, want %v", curve.Params().Name, test.name, err, wantR)
+			}
+
+			if Verify(r, test.value, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s: Verify accepted s = %s", curve.Params().Name, test.name)
+			}
+			if _, err := VerifyDetailed(r, test.value, key.PublicX, key.PublicY, curve, testMessageHash[:]); !errors.Is(err, wantS) {
+				t.Errorf("%s: s = %s: err = %v, want %v", curve.Params().Name, test.name, err, wantS)
+			}
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for _, curve := range nistCurves {
+		var n = curve.Params().N
+		for _, d := range []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(n, big.NewInt(1)), generateKey(t, curve).Private} {
+			var inv = inverse(d, n)
+			if inv.Cmp(inverseFermat(d, n)) != 0 {
+				t.Errorf("%s: inverse(%x) = %x differs from inverseFermat", curve.Params().Name, d, inv)
+			}
+			if product := new(big.Int).Mul(d, inv); product.Mod(product, n).Cmp(big.NewInt(1)) != 0 {
+				t.Errorf("%s: %x * inverse(%x) mod N is not 1", curve.Params().Name, d, d)
+			}
+		}
+
+		// 0 and N have no inverse, which is returned as the sentinel 0
+		for _, d := range []*big.Int{big.NewInt(0), new(big.Int).Set(n)} {
+			if inv := inverse(d, n); inv.Sign() != 0 {
+				t.Errorf("%s: inverse(%x) = %x, want 0", curve.Params().Name, d, inv)
+			}
+		}
+	}
+}
+
+func TestInteropCryptoECDSA(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		for i := 0; i < 20; i++ {
+			// Signature of Sign, checked by ecdsa.Verify
+			var key = generateKey(t, curve)
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pub = &ecdsa.PublicKey{Curve: curve, X: key.PublicX, Y: key.PublicY}
+			if !ecdsa.Verify(pub, testMessageHash[:], r, s) {
+				t.Fatalf("%s: ecdsa.Verify rejected the signature of Sign", curve.Params().Name)
+			}
+
+			// Signature of ecdsa.Sign, checked by Verify
+			privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, s, err = ecdsa.Sign(rand.Reader, privateKey, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, privateKey.X, privateKey.Y, curve, testMessageHash[:]) {
+				t.Fatalf("%s: Verify rejected the signature of ecdsa.Sign", curve.Params().Name)
+			}
+			if Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Fatalf("%s: Verify accepted the signature of ecdsa.Sign under another key", curve.Params().Name)
+			}
+		}
+	}
+}
+
+func TestVerifyStrictRejectsHighS(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var n = curve.Params().N
+		var half = new(big.Int).Rsh(n, 1)
+
+		for i := 0; i < 20; i++ {
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var low = NormalizeS(s, curve)
+			var high = new(big.Int).Sub(n, low)
+			if low.Cmp(half) > 0 || high.Cmp(half) <= 0 {
+				t.Fatalf("%s: NormalizeS(%x) = %x, not in the lower half", curve.Params().Name, s, low)
+			}
+			if NormalizeS(low, curve).Cmp(low) != 0 || NormalizeS(high, curve).Cmp(low) != 0 {
+				t.Fatalf("%s: NormalizeS is not idempotent on %x", curve.Params().Name, low)
+			}
+
+			var tests = []struct {
+				name               string
+				s                  *big.Int
+				permissive, strict bool
+			}{
+				{"low s", low, true, true},
+				{"N-s", high, true, false},
+			}
+			for _, test := range tests {
+				if got := Verify(r, test.s, key.PublicX, key.PublicY, curve, testMessageHash[:]); got != test.permissive {
+					t.Errorf("%s %s: Verify = %v, want %v", curve.Params().Name, test.name, got, test.permissive)
+				}
+				if got := VerifyStrict(r, test.s, key.PublicX, key.PublicY, curve, testMessageHash[:]); got != test.strict {
+					t.Errorf("%s %s: VerifyStrict = %v, want %v", curve.Params().Name, test.name, got, test.strict)
+				}
+			}
+		}
+	}
+}
+
+func TestHashToInt(t *testing.T) {
+	var sum256 = sha256.Sum256([]byte("sample"))
+	var tests = []struct {
+		name  string
+		curve elliptic.Curve
+		hash  []byte
+		want  *big.Int
+	}{
+		// 256 bit hash shifted right by 32 bits for the 224 bit N
+		{"P-224 SHA-256", elliptic.P224(), sum256[:], new(big.Int).Rsh(new(big.Int).SetBytes(sum256[:]), 32)},
+		{"P-256 SHA-256", elliptic.P256(), sum256[:], new(big.Int).SetBytes(sum256[:])},
+		{"P-521 SHA-256", elliptic.P521(), sum256[:], new(big.Int).SetBytes(sum256[:])},
+		// 66 bytes for the 521 bit N, the trailing 7 bits dropped
+		{"P-521 528 bits", elliptic.P521(), bytes.Repeat([]byte{0xff}, 66), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 521), big.NewInt(1))},
+		{"P-256 empty", elliptic.P256(), nil, new(big.Int)},
+	}
+
+	for _, test := range tests {
+		if z := HashToInt(test.hash, test.curve); z.Cmp(test.want) != 0 {
+			t.Errorf("%s: HashToInt(%x) = %x, want %x", test.name, test.hash, z, test.want)
+		}
+	}
+}
+
+func TestHashToIntInteropP224(t *testing.T) {
+	var curve = elliptic.P224()
+	var sum512 = sha512.Sum512([]byte("Take the red pill!"))
+
+	// Hashes longer than the 224 bit N, which only agree with crypto/ecdsa if
+	// both truncate to the leftmost bits
+	for _, messageHash := range [][]byte{testMessageHash[:], sum512[:]} {
+		var key = generateKey(t, curve)
+		var pub = &ecdsa.PublicKey{Curve: curve, X: key.PublicX, Y: key.PublicY}
+
+		r, s, err := Sign(key, messageHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ecdsa.Verify(pub, messageHash, r, s) {
+			t.Errorf("%d byte hash: ecdsa.Verify rejected the signature of Sign", len(messageHash))
+		}
+
+		r, s, err = ecdsa.Sign(rand.Reader, &ecdsa.PrivateKey{PublicKey: *pub, D: key.Private}, messageHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, curve, messageHash) {
+			t.Errorf("%d byte hash: Verify rejected the signature of ecdsa.Sign", len(messageHash))
+		}
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var private = key.Private
+
+		key.Zeroize()
+		if key.Private != nil {
+			t.Fatalf("%s: Private = %x after Zeroize, want nil", curve.Params().Name, key.Private)
+		}
+		if private.Sign() != 0 {
+			t.Fatalf("%s: wiped scalar = %x, want 0", curve.Params().Name, private)
+		}
+		for _, word := range private.Bits()[:cap(private.Bits())] {
+			if word != 0 {
+				t.Fatalf("%s: a word backing the wiped scalar is not 0", curve.Params().Name)
+			}
+		}
+		key.Zeroize()
+
+		// Every way of signing errors out rather than panicking, both for the
+		// nil Private and for a copy holding the wiped 0
+		for _, zeroized := range []Key{key, {Private: private, PublicX: key.PublicX, PublicY: key.PublicY, Curve: curve}} {
+			var signers = []struct {
+				name string
+				sign func() error
+			}{
+				{"Sign", func() error { _, _, err := Sign(zeroized, testMessageHash[:]); return err }},
+				{"SignWithK", func() error { _, _, err := SignWithK(zeroized, testMessageHash[:], big.NewInt(1)); return err }},
+				{"SignDeterministic", func() error { _, _, err := SignDeterministic(zeroized, testMessageHash[:]); return err }},
+				{"SignBlinded", func() error { _, err := SignBlinded(zeroized, testMessageHash[:]); return err }},
+			}
+			for _, signer := range signers {
+				if err := signer.sign(); !errors.Is(err, ErrInvalidPrivateKey) {
+					t.Errorf("%s: %s of a zeroized key: err = %v, want %v", curve.Params().Name, signer.name, err, ErrInvalidPrivateKey)
+				}
+			}
+		}
+	}
+}
+
+func TestSignContextCancelled(t *testing.T) {
+	var key = generateKey(t, elliptic.P256())
+
+	var cancelled, cancel = context.WithCancel(context.Background())
+	cancel()
+	var expired, cancelExpired = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	var tests = []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"cancelled", cancelled, context.Canceled},
+		{"deadline exceeded", expired, context.DeadlineExceeded},
+	}
+
+	for _, test := range tests {
+		var start = time.Now()
+		r, s, err := SignContext(test.ctx, key, testMessageHash[:])
+		if !errors.Is(err, test.want) || r != nil || s != nil {
+			t.Errorf("%s: SignContext = (%v, %v, %v), want %v", test.name, r, s, err, test.want)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: SignContext took %v to return", test.name, elapsed)
+		}
+	}
+
+	if _, _, err := SignContext(context.Background(), key, testMessageHash[:]); err != nil {
+		t.Fatalf("SignContext with a live context: %v", err)
+	}
+}
+
+func TestSignWithKKnownAnswer(t *testing.T) {
+	// The (k, hash, key) triples of appendix A.2.5 of RFC 6979, signed with
+	// their published k rather than the one derived by SignDeterministic
+	var key = rfc6979P256Key(t)
+	for _, test := range rfc6979P256Tests {
+		var h = test.hash.New()
+		h.Write([]byte(test.message))
+
+		r, s, err := SignWithK(key, h.Sum(nil), hexInt(test.k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(hexInt(test.r)) != 0 || s.Cmp(hexInt(test.s)) != 0 {
+			t.Errorf("%v %q: (r, s) = (%X, %X), want (%s, %s)", test.hash, test.message, r, s, test.r, test.s)
+		}
+	}
+}
+
+func TestSignWithKInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var key = generateKey(t, curve)
+	var n = curve.Params().N
+
+	for _, k := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1), new(big.Int).Set(n), new(big.Int).Add(n, big.NewInt(1))} {
+		if r, s, err := SignWithK(key, testMessageHash[:], k); err == nil {
+			t.Errorf("SignWithK with k = %v = (%v, %v), want an error", k, r, s)
+		}
+	}
+}
+
+func TestVerifyDetailedFailures(t *testing.T) {
+	for _, curve := range nistCurves {
+		var key, other = generateKey(t, curve), generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n = curve.Params().N
+		var otherHash = sha256.Sum256([]byte("Take the green pill!"))
+
+		var tests = []struct {
+			name       string
+			r, s, x, y *big.Int
+			hash       []byte
+			want       error
+		}{
+			{"valid", r, s, key.PublicX, key.PublicY, testMessageHash[:], nil},
+			{"r = N", n, s, key.PublicX, key.PublicY, testMessageHash[:], ErrROutOfRange},
+			{"s = 0", r, big.NewInt(0), key.PublicX, key.PublicY, testMessageHash[:], ErrSOutOfRange},
+			{"public key at infinity", r, s, new(big.Int), new(big.Int), testMessageHash[:], ErrIdentityPublicKey},
+			{"public key off the curve", r, s, key.PublicX, new(big.Int).Add(key.PublicY, big.NewInt(1)), testMessageHash[:], ErrPointNotOnCurve},
+			{"other public key", r, s, other.PublicX, other.PublicY, testMessageHash[:], ErrRecomputedRMismatch},
+			{"other hash", r, s, key.PublicX, key.PublicY, otherHash[:], ErrRecomputedRMismatch},
+			{"r off by one", new(big.Int).Add(r, big.NewInt(1)), s, key.PublicX, key.PublicY, testMessageHash[:], ErrRecomputedRMismatch},
+		}
+
+		for _, test := range tests {
+			valid, err := VerifyDetailed(test.r, test.s, test.x, test.y, curve, test.hash)
+			if valid != (test.want == nil) || !errors.Is(err, test.want) {
+				t.Errorf("%s %s: VerifyDetailed = (%v, %v), want %v", curve.Params().Name, test.name, valid, err, test.want)
+			}
+			if got := Verify(test.r, test.s, test.x, test.y, curve, test.hash); got != valid {
+				t.Errorf("%s %s: Verify = %v differs from VerifyDetailed", curve.Params().Name, test.name, got)
+			}
+		}
+	}
+}
+
+func TestVerifyInfinityPoint(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var n = curve.Params().N
+
+		// With z = -re mod N, uG + vP = (z + re)/s G is the point at infinity
+		// for every s, no matter the r
+		var r = big.NewInt(1)
+		var z = new(big.Int).Mul(r, key.Private)
+		z.Neg(z).Mod(z, n)
+		// Shifted into the leftmost N.BitLen() bits, as HashToInt takes them
+		var byteLen = scalarByteLen(curve)
+		var messageHash = new(big.Int).Lsh(z, uint(byteLen*8-n.BitLen())).FillBytes(make([]byte, byteLen))
+		if HashToInt(messageHash, curve).Cmp(z) != 0 {
+			t.Fatalf("%s: HashToInt does not give z back", curve.Params().Name)
+		}
+
+		for _, s := range []*big.Int{big.NewInt(1), big.NewInt(7), new(big.Int).Sub(n, big.NewInt(1))} {
+			if _, err := VerifyDetailed(r, s, key.PublicX, key.PublicY, curve, messageHash); !errors.Is(err, ErrInfinityPoint) {
+				t.Errorf("%s: VerifyDetailed with s = %x: err = %v, want %v", curve.Params().Name, s, err, ErrInfinityPoint)
+			}
+			if Verify(r, s, key.PublicX, key.PublicY, curve, messageHash) || VerifyFast(r, s, key.PublicX, key.PublicY, curve, messageHash) {
+				t.Errorf("%s: signature with uG + vP at infinity accepted", curve.Params().Name)
+			}
+		}
+	}
+}
+
+func TestSignVerifyDigestLengths(t *testing.T) {
+	var message = []byte("Take the red pill!")
+	var sum384 = sha512.Sum384(message)
+	var sum512 = sha512.Sum512(message)
+
+	var tests = []struct {
+		name  string
+		curve elliptic.Curve
+		hash  []byte
+	}{
+		{"P-384 SHA-384", elliptic.P384(), sum384[:]},
+		{"P-521 SHA-512", elliptic.P521(), sum512[:]},
+		{"P-256 SHA-512", elliptic.P256(), sum512[:]},
+		{"P-384 SHA-256", elliptic.P384(), testMessageHash[:]},
+		{"P-521 SHA-256", elliptic.P521(), testMessageHash[:]},
+		{"P-256 1 byte", elliptic.P256(), message[:1]},
+	}
+
+	for _, test := range tests {
+		var key = generateKey(t, test.curve)
+		r, s, err := Sign(key, test.hash)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, test.curve, test.hash) {
+			t.Errorf("%s: signature does not verify", test.name)
+		}
+		if !ecdsa.Verify(&ecdsa.PublicKey{Curve: test.curve, X: key.PublicX, Y: key.PublicY}, test.hash, r, s) {
+			t.Errorf("%s: ecdsa.Verify rejected the signature", test.name)
+		}
+
+		// Another digest of the same length differs in its leftmost bits
+		var other = append([]byte{test.hash[0] ^ 0x80}, test.hash[1:]...)
+		if Verify(r, s, key.PublicX, key.PublicY, test.curve, other) {
+			t.Errorf("%s: signature verifies for another digest", test.name)
+		}
+	}
+}
+
+func TestSignVerifyProperty(t *testing.T) {
+	var iterations = 1000
+	if testing.Short() {
+		iterations = 50
+	}
+
+	for _, curve := range nistCurves {
+		var failures int
+		var key Key
+		for i := 0; i < iterations; i++ {
+			// A fresh key every tenth iteration, and a random hash of 1 to
+			// 64 bytes every time
+			if i%10 == 0 {
+				key = generateKey(t, curve)
+			}
+			var messageHash = make([]byte, 1+i%MaxMessageHashSize)
+			if _, err := rand.Read(messageHash); err != nil {
+				t.Fatal(err)
+			}
+
+			r, s, err := Sign(key, messageHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, messageHash) {
+				failures++
+			}
+		}
+		if failures != 0 {
+			t.Errorf("%s: %d of %d signatures do not verify", curve.Params().Name, failures, iterations)
+		}
+	}
+}
+
+func TestP521KnownAnswer(t *testing.T) {
+	// Appendix A.2.7 of RFC 6979, SHA-256 and message "sample"
+	var curve = elliptic.P521()
+	var key = Key{Private: hexInt("00FAD06DAA62BA3B25D2FB40133DA757205DE67F5BB0018FEE8C86E1B68C7E75CAA896EB32F1F47C70855836A6D16FCC1466F6D8FBEC67DB89EC0C08B0E996B83538"), Curve: curve}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+	if key.PublicX.Cmp(hexInt("01894550D0785932E00EAA23B694F213F8C3121F86DC97A04E5A7167DB4E5BCD371123D46E45DB6B5D5370A7F20FB633155D38FFA16D2BD761DCAC474B9A2F5023A4")) != 0 ||
+		key.PublicY.Cmp(hexInt("00493101C962CD4D2FDDF782285E64584139C2F91B47F87FF82354D6630F746A28A0DB25741B5B34A828008B22ACC23F924FAAFBD4D33F81EA66956DFEAA2BFDFCF5")) != 0 {
+		t.Fatalf("public key (%x, %x) differs from RFC 6979", key.PublicX, key.PublicY)
+	}
+
+	var messageHash = sha256.Sum256([]byte("sample"))
+	var k = hexInt("00EDF38AFCAAECAB4383358B34D67C9F2216C8382AAEA44A3DAD5FDC9C32575761793FEF24EB0FC276DFC4F6E3EC476752F043CF01415387470BCBD8678ED2C7E1A0")
+	var wantR = hexInt("01511BB4D675114FE266FC4372B87682BAECC01D3CC62CF2303C92B3526012659D16876E25C7C1E57648F23B73564D67F61C6F14D527D54972810421E7D87589E1A7")
+	var wantS = hexInt("004A171143A83163D6DF460AAF61522695F207A58B95C0644D87E52AA1A347916E4F7A72930B1BC06DBE22CE3F58264AFD23704CBB63B29B931F7DE6C9D949A7ECFC")
+
+	r, s, err := SignWithK(key, messageHash[:], k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		t.Fatalf("SignWithK = (%X, %X), want (%X, %X)", r, s, wantR, wantS)
+	}
+	if r, s, err = SignDeterministic(key, messageHash[:]); err != nil || r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		t.Fatalf("SignDeterministic = (%X, %X, %v), want (%X, %X)", r, s, err, wantR, wantS)
+	}
+	if !Verify(wantR, wantS, key.PublicX, key.PublicY, curve, messageHash[:]) {
+		t.Fatal("Verify rejected the known signature")
+	}
+}
+
+func TestP521Encodings(t *testing.T) {
+	// The 521 bit field and order take 66 bytes, the top byte holding a
+	// single bit
+	var curve = elliptic.P521()
+	var sum512 = sha512.Sum512([]byte("Take the red pill!"))
+
+	for i := 0; i < 20; i++ {
+		var key = generateKey(t, curve)
+		sig, err := SignV2(key, sum512[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyV2(sig, key.PublicKey(), sum512[:]) {
+			t.Fatal("signature does not verify")
+		}
+
+		if got := len(MarshalCompressed(key)); got != 67 {
+			t.Fatalf("compressed public key of %d bytes, want 67", got)
+		}
+		if got := len(key.PrivateBytes()); got != 66 {
+			t.Fatalf("private key of %d bytes, want 66", got)
+		}
+
+		var fixed = EncodeSignatureFixed(sig, curve)
+		if len(fixed) != 132 || SignatureByteLen(curve) != 132 {
+			t.Fatalf("fixed signature of %d bytes, want 132", len(fixed))
+		}
+		decoded, err := DecodeSignatureFixed(fixed, curve)
+		if err != nil || decoded.R.Cmp(sig.R) != 0 || decoded.S.Cmp(sig.S) != 0 {
+			t.Fatalf("DecodeSignatureFixed = (%v, %v), want %v", decoded, err, sig)
+		}
+	}
+}
+
+func TestKeyValidate(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key, other = generateKey(t, curve), generateKey(t, curve)
+		if err := key.Validate(); err != nil {
+			t.Fatalf("%s: Validate of a generated key: %v", curve.Params().Name, err)
+		}
+
+		var n = curve.Params().N
+		var tests = []struct {
+			name string
+			key  Key
+			want error
+		}{
+			{"tampered PublicX", Key{key.Private, new(big.Int).Add(key.PublicX, big.NewInt(1)), key.PublicY, curve}, ErrKeyMismatch},
+			{"public key of another key", Key{key.Private, other.PublicX, other.PublicY, curve}, ErrKeyMismatch},
+			{"missing PublicY", Key{key.Private, key.PublicX, nil, curve}, ErrKeyMismatch},
+			{"Private = 0", Key{big.NewInt(0), key.PublicX, key.PublicY, curve}, ErrInvalidPrivateKey},
+			{"Private = N", Key{new(big.Int).Set(n), key.PublicX, key.PublicY, curve}, ErrInvalidPrivateKey},
+			{"Private = -1", Key{big.NewInt(-1), key.PublicX, key.PublicY, curve}, ErrInvalidPrivateKey},
+			{"missing Private", Key{nil, key.PublicX, key.PublicY, curve}, ErrInvalidPrivateKey},
+			{"missing curve", Key{key.Private, key.PublicX, key.PublicY, nil}, ErrInvalidPrivateKey},
+		}
+
+		for _, test := range tests {
+			if err := test.key.Validate(); !errors.Is(err, test.want) {
+				t.Errorf("%s %s: Validate = %v, want %v", curve.Params().Name, test.name, err, test.want)
+			}
+		}
+	}
+}
+
+func TestSignFullPoint(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		setNonceSource(t, bytes.NewReader(candidateBuffer(curve, new(big.Int).Sub(k, big.NewInt(1)))))
+
+		r, s, Rx, Ry, err := SignFull(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// R = kG, with r = Rx mod N, and the parity of Ry selecting the
+		// recovery id that gives back the public key
+		wantX, wantY := curve.ScalarBaseMult(k.Bytes())
+		if Rx.Cmp(wantX) != 0 || Ry.Cmp(wantY) != 0 {
+			t.Fatalf("%s: R = (%x, %x), want kG = (%x, %x)", curve.Params().Name, Rx, Ry, wantX, wantY)
+		}
+		if r.Cmp(new(big.Int).Mod(Rx, curve.Params().N)) != 0 {
+			t.Fatalf("%s: r = %x, want Rx mod N", curve.Params().Name, r)
+		}
+		if wantR, wantS, _ := SignWithK(key, testMessageHash[:], k); r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+			t.Fatalf("%s: (r, s) differs from SignWithK of the same k", curve.Params().Name)
+		}
+		x, y, err := RecoverPublicKey(Signature{R: r, S: s}, int(Ry.Bit(0)), curve, testMessageHash[:])
+		if err != nil || x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+			t.Fatalf("%s: recovery id of the parity of Ry gave (%x, %x, %v)", curve.Params().Name, x, y, err)
+		}
+	}
+}
+
+func TestSignSmallPrivateScalar(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		for _, d := range []int64{1, 2, 3, 0xff, 0x100, 0xffff} {
+			var key = Key{Private: big.NewInt(d), Curve: curve}
+			key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+
+			// dG from the minimal big-endian bytes of d
+			var x, y = curve.ScalarBaseMult(big.NewInt(d).Bytes())
+			if key.PublicX.Cmp(x) != 0 || key.PublicY.Cmp(y) != 0 {
+				t.Fatalf("%s d = %d: public key (%x, %x), want (%x, %x)", curve.Params().Name, d, key.PublicX, key.PublicY, x, y)
+			}
+
+			if n := len(scalarBytes(key.Private, curve)); n != scalarByteLen(curve) {
+				t.Fatalf("%s d = %d: len(scalarBytes) = %d, want %d", curve.Params().Name, d, n, scalarByteLen(curve))
+			}
+
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s d = %d: signature does not verify", curve.Params().Name, d)
+			}
+			if curve != Secp256k1() && !ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, testMessageHash[:], r, s) {
+				t.Errorf("%s d = %d: ecdsa.Verify rejected the signature", curve.Params().Name, d)
+			}
+		}
+	}
+}
+
+func TestNewKeyFromScalar(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var valid = generateKey(t, curve)
+
+		var tests = []struct {
+			name string
+			d    *big.Int
+			ok   bool
+		}{
+			{"valid", valid.Private, true},
+			{"d = 1", big.NewInt(1), true},
+			{"d = N-1", new(big.Int).Sub(n, big.NewInt(1)), true},
+			{"d = 0", new(big.Int), false},
+			{"d = N", new(big.Int).Set(n), false},
+			{"d > N", new(big.Int).Add(n, big.NewInt(1)), false},
+			{"nil", nil, false},
+		}
+
+		for _, test := range tests {
+			key, err := NewKeyFromScalar(curve, test.d)
+			if !test.ok {
+				if !errors.Is(err, ErrInvalidPrivateKey) {
+					t.Errorf("%s %s: NewKeyFromScalar err = %v, want %v", curve.Params().Name, test.name, err, ErrInvalidPrivateKey)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s %s: %v", curve.Params().Name, test.name, err)
+			}
+			wantX, wantY := curve.ScalarBaseMult(test.d.Bytes())
+			if key.PublicX.Cmp(wantX) != 0 || key.PublicY.Cmp(wantY) != 0 || key.Validate() != nil {
+				t.Errorf("%s %s: public key (%x, %x), want (%x, %x)", curve.Params().Name, test.name, key.PublicX, key.PublicY, wantX, wantY)
+			}
+
+			// The scalar is copied
+			if key.Private == test.d {
+				t.Errorf("%s %s: NewKeyFromScalar kept the caller's *big.Int", curve.Params().Name, test.name)
+			}
+
+			// The same key from the bytes of d, padded or not
+			for _, b := range [][]byte{test.d.Bytes(), test.d.FillBytes(make([]byte, scalarByteLen(curve)))} {
+				fromBytes, err := NewKeyFromBytes(curve, b)
+				if err != nil {
+					t.Fatalf("%s %s: NewKeyFromBytes(%x): %v", curve.Params().Name, test.name, b, err)
+				}
+				if fromBytes.Private.Cmp(key.Private) != 0 || fromBytes.PublicX.Cmp(key.PublicX) != 0 || fromBytes.PublicY.Cmp(key.PublicY) != 0 {
+					t.Errorf("%s %s: NewKeyFromBytes(%x) differs from NewKeyFromScalar", curve.Params().Name, test.name, b)
+				}
+			}
+		}
+
+		for _, b := range [][]byte{nil, make([]byte, scalarByteLen(curve)), n.Bytes()} {
+			if _, err := NewKeyFromBytes(curve, b); !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("%s: NewKeyFromBytes(%x) err = %v, want %v", curve.Params().Name, b, err, ErrInvalidPrivateKey)
+			}
+		}
+	}
+}
+
+func TestSignComponents(t *testing.T) {
+	var check = func(name string, key Key, messageHash []byte, k *big.Int) SignatureComponents {
+		t.Helper()
+		var n = key.Curve.Params().N
+		c, err := SignComponents(key, messageHash, k)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// Each component recomputed from its definition
+		var Rx, _ = key.Curve.ScalarBaseMult(k.Bytes())
+		if want := new(big.Int).Mod(Rx, n); c.R.Cmp(want) != 0 {
+			t.Errorf("%s: R = %x, want (kG).x mod N = %x", name, c.R, want)
+		}
+		if want := HashToInt(messageHash, key.Curve); c.Z.Cmp(want) != 0 {
+			t.Errorf("%s: Z = %x, want %x", name, c.Z, want)
+		}
+		if want := new(big.Int).Mod(new(big.Int).Mul(c.R, key.Private), n); c.RE.Cmp(want) != 0 {
+			t.Errorf("%s: RE = %x, want %x", name, c.RE, want)
+		}
+		if one := new(big.Int).Mod(new(big.Int).Mul(c.KInv, k), n); one.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("%s: KInv * k = %x, want 1", name, one)
+		}
+
+		// s recombined as (z + re) * k^-1 mod N
+		var s = new(big.Int).Add(c.Z, c.RE)
+		s.Mul(s, c.KInv)
+		s.Mod(s, n)
+		if s.Cmp(c.S) != 0 {
+			t.Errorf("%s: (Z + RE) * KInv = %x, want S = %x", name, s, c.S)
+		}
+		if r, wantS, _ := SignWithK(key, messageHash, k); c.R.Cmp(r) != 0 || c.S.Cmp(wantS) != 0 {
+			t.Errorf("%s: (R, S) differs from SignWithK", name)
+		}
+		return c
+	}
+
+	// SHA-256 and "sample" of RFC 6979 A.2.5, with the published r and s
+	var key = rfc6979P256Key(t)
+	var test = rfc6979P256Tests[2]
+	var messageHash = sha256.Sum256([]byte(test.message))
+	if c := check("RFC 6979", key, messageHash[:], hexInt(test.k)); c.R.Cmp(hexInt(test.r)) != 0 || c.S.Cmp(hexInt(test.s)) != 0 {
+		t.Errorf("RFC 6979: (R, S) = (%X, %X), want (%s, %s)", c.R, c.S, test.r, test.s)
+	}
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(curve.Params().Name, generateKey(t, curve), testMessageHash[:], k)
+	}
+}
+
+func TestVerifyIdentityPublicKey(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var x, y = new(big.Int), new(big.Int)
+		var identity = PublicKey{X: x, Y: y, Curve: curve}
+
+		if err := ValidatePublicKey(curve, x, y); !errors.Is(err, ErrIdentityPublicKey) {
+			t.Errorf("%s: ValidatePublicKey(0, 0) = %v, want %v", curve.Params().Name, err, ErrIdentityPublicKey)
+		}
+		for _, verify := range []func() (bool, error){
+			func() (bool, error) { return VerifyDetailed(r, s, x, y, curve, testMessageHash[:]) },
+			func() (bool, error) { return VerifyCrosscheck(r, s, x, y, curve, testMessageHash[:]) },
+		} {
+			if valid, err := verify(); valid || !errors.Is(err, ErrIdentityPublicKey) {
+				t.Errorf("%s: verification with (0, 0) = (%v, %v), want %v", curve.Params().Name, valid, err, ErrIdentityPublicKey)
+			}
+		}
+
+		var tests = []struct {
+			name   string
+			verify func() bool
+		}{
+			{"Verify", func() bool { return Verify(r, s, x, y, curve, testMessageHash[:]) }},
+			{"VerifyStrict", func() bool { return VerifyStrict(r, NormalizeS(s, curve), x, y, curve, testMessageHash[:]) }},
+			{"VerifyFast", func() bool { return VerifyFast(r, s, x, y, curve, testMessageHash[:]) }},
+			{"VerifyV2", func() bool { return VerifyV2(Signature{R: r, S: s}, identity, testMessageHash[:]) }},
+		}
+		for _, test := range tests {
+			if test.verify() {
+				t.Errorf("%s: %s accepted the public key (0, 0)", curve.Params().Name, test.name)
+			}
+		}
+
+		// Rejected before any scalar multiplication
+		for _, fast := range []bool{false, true} {
+			var stats VerifyStats
+			if VerifyWithStats(r, s, x, y, curve, testMessageHash[:], fast, &stats) {
+				t.Errorf("%s fast %v: VerifyWithStats accepted the public key (0, 0)", curve.Params().Name, fast)
+			}
+			if stats.ScalarMults != 0 || stats.DoubleScalarMults != 0 || stats.PointAdds != 0 {
+				t.Errorf("%s fast %v: stats = %+v, want no point arithmetic", curve.Params().Name, fast, stats)
+			}
+		}
+	}
+}
+
+func TestVerifyPrecomputedMatchesVerify(t *testing.T) {
+	var sha512Hash = sha512.Sum512([]byte("Take the red pill!"))
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var pub = key.PublicKey()
+
+		for _, messageHash := range [][]byte{testMessageHash[:], sha512Hash[:]} {
+			sig, err := SignV2(key, messageHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var otherKey = generateKey(t, curve).PublicKey()
+
+			var tests = []struct {
+				name string
+				sig  Signature
+				pub  PublicKey
+			}{
+				{"valid", sig, pub},
+				{"r + 1", Signature{R: new(big.Int).Add(sig.R, big.NewInt(1)), S: sig.S}, pub},
+				{"-s", Signature{R: sig.R, S: new(big.Int).Sub(curve.Params().N, sig.S)}, pub},
+				{"s = 0", Signature{R: sig.R, S: new(big.Int)}, pub},
+				{"other key", sig, otherKey},
+			}
+
+			var z = HashToInt(messageHash, curve)
+			for _, test := range tests {
+				var want = Verify(test.sig.R, test.sig.S, test.pub.X, test.pub.Y, curve, messageHash)
+				if got := VerifyPrecomputed(z, test.sig, test.pub); got != want {
+					t.Errorf("%s %d byte hash %s: VerifyPrecomputed = %v, want %v of Verify", curve.Params().Name, len(messageHash), test.name, got, want)
+				}
+			}
+			if !VerifyPrecomputed(z, sig, pub) {
+				t.Errorf("%s %d byte hash: VerifyPrecomputed rejected a valid signature", curve.Params().Name, len(messageHash))
+			}
+		}
+
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if VerifyPrecomputed(nil, sig, pub) || VerifyPrecomputed(HashToInt(testMessageHash[:], curve), sig, PublicKey{X: pub.X, Y: pub.Y}) {
+			t.Errorf("%s: VerifyPrecomputed accepted a missing z or curve", curve.Params().Name)
+		}
+	}
+}
+
+func TestSignConcurrentKey(t *testing.T) {
+	// One Key shared by 100 goroutines, each signing and verifying its own
+	// message with every signing path; run with -race
+	for _, curve := range []elliptic.Curve{elliptic.P256(), Secp256k1()} {
+		var key = generateKey(t, curve)
+		var private, publicX, publicY = new(big.Int).Set(key.Private), new(big.Int).Set(key.PublicX), new(big.Int).Set(key.PublicY)
+
+		var wg sync.WaitGroup
+		var errs = make(chan error, 100)
+		for g := 0; g < 100; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				var messageHash = sha256.Sum256([]byte(fmt.Sprintf("message %d", g)))
+
+				r, s, err := Sign(key, messageHash[:])
+				if err != nil {
+					errs <- err
+					return
+				}
+				dr, ds, err := SignDeterministic(key, messageHash[:])
+				if err != nil {
+					errs <- err
+					return
+				}
+				blinded, err := SignBlinded(key, messageHash[:])
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				for _, sig := range []Signature{{R: r, S: s}, {R: dr, S: ds}, blinded} {
+					if !Verify(sig.R, sig.S, key.PublicX, key.PublicY, curve, messageHash[:]) {
+						errs <- fmt.Errorf("goroutine %d: signature does not verify", g)
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("%s: %v", curve.Params().Name, err)
+		}
+
+		if key.Private.Cmp(private) != 0 || key.PublicX.Cmp(publicX) != 0 || key.PublicY.Cmp(publicY) != 0 {
+			t.Errorf("%s: concurrent signing changed the key", curve.Params().Name)
+		}
+	}
+}
+
+func TestModN(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var nMinus1 = new(big.Int).Sub(n, big.NewInt(1))
+
+		var tests = []struct {
+			name string
+			x    *big.Int
+			want *big.Int
+		}{
+			{"0", big.NewInt(0), big.NewInt(0)},
+			{"1", big.NewInt(1), big.NewInt(1)},
+			{"N - 1", nMinus1, nMinus1},
+			{"N", new(big.Int).Set(n), big.NewInt(0)},
+			{"N + 5", new(big.Int).Add(n, big.NewInt(5)), big.NewInt(5)},
+			{"-1", big.NewInt(-1), nMinus1},
+			{"-N", new(big.Int).Neg(n), big.NewInt(0)},
+			{"-N - 1", new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)), nMinus1},
+			{"-3N + 7", new(big.Int).Add(new(big.Int).Mul(n, big.NewInt(-3)), big.NewInt(7)), big.NewInt(7)},
+		}
+
+		for _, test := range tests {
+			var x = new(big.Int).Set(test.x)
+			if got := modN(x, curve); got.Cmp(test.want) != 0 || got.Sign() < 0 || got.Cmp(n) >= 0 {
+				t.Errorf("%s: modN(%s) = %v, want %v", curve.Params().Name, test.name, got, test.want)
+			}
+			if x.Cmp(test.x) != 0 {
+				t.Errorf("%s: modN(%s) changed its input", curve.Params().Name, test.name)
+			}
+			// In place, as for the intermediates of Sign and Verify
+			if got := modNTo(x, x, curve); got != x || x.Cmp(test.want) != 0 {
+				t.Errorf("%s: modNTo(x, %s) = %v, want %v in x", curve.Params().Name, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestPrivateBytes(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var byteLen = (curve.Params().N.BitLen() + 7) / 8
+		var tests = []struct {
+			name    string
+			private *big.Int
+		}{
+			{"1", big.NewInt(1)},
+			{"0xff", big.NewInt(0xff)},
+			{"2^64", new(big.Int).Lsh(big.NewInt(1), 64)},
+			{"N - 1", new(big.Int).Sub(curve.Params().N, big.NewInt(1))},
+		}
+
+		for _, test := range tests {
+			key, err := NewKeyFromScalar(curve, test.private)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b = key.PrivateBytes()
+			if len(b) != byteLen {
+				t.Errorf("%s %s: PrivateBytes of %d bytes, want %d", curve.Params().Name, test.name, len(b), byteLen)
+				continue
+			}
+			if new(big.Int).SetBytes(b).Cmp(test.private) != 0 {
+				t.Errorf("%s %s: PrivateBytes = %x, want %x", curve.Params().Name, test.name, b, test.private)
+			}
+			var minimal = test.private.Bytes()
+			if !bytes.Equal(b[byteLen-len(minimal):], minimal) || !bytes.Equal(b[:byteLen-len(minimal)], make([]byte, byteLen-len(minimal))) {
+				t.Errorf("%s %s: PrivateBytes = %x, want %x left-padded with zeros", curve.Params().Name, test.name, b, minimal)
+			}
+		}
+
+		if b := (Key{Curve: curve}).PrivateBytes(); b != nil {
+			t.Errorf("%s: PrivateBytes without a private scalar = %x, want nil", curve.Params().Name, b)
+		}
+	}
+	if b := (Key{Private: big.NewInt(1)}).PrivateBytes(); b != nil {
+		t.Errorf("PrivateBytes without a curve = %x, want nil", b)
+	}
+}
+
+func TestHashTooLong(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+
+		// Hashes up to MaxMessageHashSize, or the byte size of N for P-521,
+		// are accepted, anything longer is not
+		var maxLen = MaxMessageHashSize
+		if byteLen := (curve.Params().N.BitLen() + 7) / 8; byteLen > maxLen {
+			maxLen = byteLen
+		}
+
+		var tests = []struct {
+			size int
+			want error
+		}{
+			{1, nil},
+			{maxLen, nil},
+			{maxLen + 1, ErrHashTooLong},
+			{10 << 20, ErrHashTooLong},
+		}
+
+		for _, test := range tests {
+			var messageHash = make([]byte, test.size)
+			messageHash[0] = 0x01
+
+			r, s, err := Sign(key, messageHash)
+			if !errors.Is(err, test.want) {
+				t.Errorf("%s: Sign of %d bytes: err = %v, want %v", curve.Params().Name, test.size, err, test.want)
+			}
+			if _, _, err := SignDeterministic(key, messageHash); !errors.Is(err, test.want) {
+				t.Errorf("%s: SignDeterministic of %d bytes: err = %v, want %v", curve.Params().Name, test.size, err, test.want)
+			}
+			if _, err := SignV2(key, messageHash); !errors.Is(err, test.want) {
+				t.Errorf("%s: SignV2 of %d bytes: err = %v, want %v", curve.Params().Name, test.size, err, test.want)
+			}
+			if test.want != nil {
+				// Verification of a valid signature rejects the same hash
+				if r, s, err = Sign(key, messageHash[:maxLen]); err != nil {
+					t.Fatal(err)
+				}
+				if valid, err := VerifyDetailed(r, s, key.PublicX, key.PublicY, curve, messageHash); valid || !errors.Is(err, test.want) {
+					t.Errorf("%s: VerifyDetailed of %d bytes = %v, %v, want false, %v", curve.Params().Name, test.size, valid, err, test.want)
+				}
+				continue
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, messageHash) {
+				t.Errorf("%s: signature of %d bytes does not verify", curve.Params().Name, test.size)
+			}
+		}
+	}
+}
+
+func TestKeyStringMasksPrivate(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var secrets = []string{
+			key.Private.String(),
+			fmt.Sprintf("%x", key.Private),
+			fmt.Sprintf("%X", key.Private),
+			fmt.Sprintf("%x", key.PrivateBytes()),
+		}
+
+		var outputs = []string{
+			key.String(),
+			fmt.Sprint(key),
+			fmt.Sprintln(key),
+			fmt.Sprintf("%v", key),
+			fmt.Sprintf("%+v", key),
+			fmt.Sprintf("%#v", key),
+			fmt.Sprintf("%s", key),
+			fmt.Sprintf("%d", key),
+			fmt.Sprintf("%x", key),
+			fmt.Sprintf("%X", key),
+			fmt.Sprintf("%q", key),
+			fmt.Sprintf("%v", &key),
+			fmt.Sprintf("%+v", struct{ Key Key }{key}),
+			fmt.Sprintf("%v", []Key{key}),
+		}
+
+		var want = fmt.Sprintf("Private: <masked, %d bytes>", (curve.Params().N.BitLen()+7)/8)
+		for i, output := range outputs {
+			for _, secret := range secrets {
+				if strings.Contains(output, secret) {
+					t.Errorf("%s: output %d %q shows the private scalar", curve.Params().Name, i, output)
+				}
+			}
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: output %d %q lacks %q", curve.Params().Name, i, output, want)
+			}
+		}
+
+		if s := key.String(); !strings.Contains(s, curve.Params().Name) || !strings.Contains(s, fmt.Sprintf("%x", key.PublicX)) || !strings.Contains(s, fmt.Sprintf("%x", key.PublicY)) {
+			t.Errorf("%s: String = %q, want the curve and public point", curve.Params().Name, s)
+		}
+	}
+
+	if s := (Key{}).String(); s != "Key{Curve: <nil>, PublicX: <nil>, PublicY: <nil>, Private: <nil>}" {
+		t.Errorf("String of the zero Key = %q", s)
+	}
+}
+
+func TestVerifyParallel(t *testing.T) {
+	// Shared signatures, half of them of another hash, verified at once from
+	// many goroutines; run with -race to check that verification holds no
+	// shared state, e.g. in the scratch pool
+	for _, curve := range []elliptic.Curve{elliptic.P256(), Secp256k1()} {
+		var keys []Key
+		var sigs []Signature
+		var hashes [][]byte
+		var valid []bool
+		for i := 0; i < 8; i++ {
+			var key = generateKey(t, curve)
+			var messageHash = sha256.Sum256([]byte{byte(i)})
+			sig, err := SignV2(key, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key)
+			sigs = append(sigs, sig)
+			if i%2 == 1 {
+				messageHash[0] ^= 0x01
+			}
+			hashes = append(hashes, messageHash[:])
+			valid = append(valid, i%2 == 0)
+		}
+
+		var wg sync.WaitGroup
+		var errs = make(chan error, 8)
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < len(keys); i++ {
+					var j = (g + i) % len(keys)
+					var got = VerifyV2(sigs[j], keys[j].PublicKey(), hashes[j])
+					if i%2 == 1 {
+						got, _ = VerifyDetailed(sigs[j].R, sigs[j].S, keys[j].PublicX, keys[j].PublicY, curve, hashes[j])
+					}
+					if got != valid[j] {
+						errs <- fmt.Errorf("goroutine %d: verification of signature %d = %v, want %v", g, j, got, valid[j])
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("%s: %v", curve.Params().Name, err)
+		}
+	}
+}
+
+// Whether f panics
+func panics(f func()) (panicked bool) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+		}
+	}()
+	f()
+	return false
+}
+
+func TestDebugChecks(t *testing.T) {
+	var n = elliptic.P256().Params().N
+	var d = big.NewInt(12345)
+	var inv = inverse(d, n)
+	var wrong = new(big.Int).Add(inv, big.NewInt(1))
+	// An inverse computed for another modulus, as a broken N would give
+	var otherModulus = inverse(d, elliptic.P384().Params().N)
+
+	var tests = []struct {
+		name   string
+		on     bool
+		inv    *big.Int
+		panics bool
+	}{
+		{"off, correct", false, inv, false},
+		{"off, wrong", false, wrong, false},
+		{"off, other modulus", false, otherModulus, false},
+		{"on, correct", true, inv, false},
+		{"on, wrong", true, wrong, true},
+		{"on, other modulus", true, otherModulus, true},
+	}
+
+	for _, test := range tests {
+		setDebugChecks(t, test.on)
+		if got := panics(func() { checkInverse(d, test.inv, n) }); got != test.panics {
+			t.Errorf("%s: checkInverse panics = %v, want %v", test.name, got, test.panics)
+		}
+	}
+
+	// With the checks on, signing and verification pass them, so that every
+	// inverse is that of a prime N
+	setDebugChecks(t, true)
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		if panics(func() {
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s: signature does not verify with DebugChecks", curve.Params().Name)
+			}
+		}) {
+			t.Errorf("%s: Sign or Verify panicked with DebugChecks", curve.Params().Name)
+		}
+
+		// An inverse of s supplied to verification is checked as well
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var badInvS = new(big.Int).Add(inverse(s, curve.Params().N), big.NewInt(1))
+		if !panics(func() {
+			verify(r, s, badInvS, key.PublicX, key.PublicY, curve, testMessageHash[:], scalarMultCombination)
+		}) {
+			t.Errorf("%s: verify with a wrong inverse of s did not panic", curve.Params().Name)
+		}
+	}
+}
+
+func TestIsValidScalar(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var tests = []struct {
+			name string
+			x    *big.Int
+			want bool
+		}{
+			{"0", big.NewInt(0), false},
+			{"1", big.NewInt(1), true},
+			{"2", big.NewInt(2), true},
+			{"N - 1", new(big.Int).Sub(n, big.NewInt(1)), true},
+			{"N", new(big.Int).Set(n), false},
+			{"N + 1", new(big.Int).Add(n, big.NewInt(1)), false},
+			{"-1", big.NewInt(-1), false},
+			{"-(N - 1)", new(big.Int).Neg(new(big.Int).Sub(n, big.NewInt(1))), false},
+			{"P", new(big.Int).Set(curve.Params().P), curve.Params().P.Cmp(n) < 0},
+			{"nil", nil, false},
+		}
+
+		for _, test := range tests {
+			if got := IsValidScalar(test.x, curve); got != test.want {
+				t.Errorf("%s: IsValidScalar(%s) = %v, want %v", curve.Params().Name, test.name, got, test.want)
+			}
+			if test.x == nil {
+				continue
+			}
+
+			// Key constructors and SignWithK apply the same range
+			if _, err := NewKeyFromScalar(curve, test.x); (err == nil) != test.want {
+				t.Errorf("%s: NewKeyFromScalar(%s) = %v, want ok %v", curve.Params().Name, test.name, err, test.want)
+			}
+			if test.want {
+				var key = generateKey(t, curve)
+				if _, _, err := SignWithK(key, testMessageHash[:], test.x); err != nil && !errors.Is(err, ErrZeroSignature) {
+					t.Errorf("%s: SignWithK(k = %s) = %v", curve.Params().Name, test.name, err)
+				}
+			} else if _, _, err := SignWithK(generateKey(t, curve), testMessageHash[:], test.x); !errors.Is(err, ErrInvalidK) {
+				t.Errorf("%s: SignWithK(k = %s) err = %v, want %v", curve.Params().Name, test.name, err, ErrInvalidK)
+			}
+		}
+	}
+
+	if IsValidScalar(big.NewInt(1), nil) {
+		t.Error("IsValidScalar without a curve = true")
+	}
+}
+
+func TestAddPointsInfinity(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var negY = new(big.Int).Sub(curve.Params().P, key.PublicY)
+		if x, y := addPoints(curve, key.PublicX, key.PublicY, key.PublicX, negY); x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("%s: P + (-P) = (%x, %x), want (0, 0)", curve.Params().Name, x, y)
+		}
+		if x, y := addPoints(curve, key.PublicX, key.PublicY, key.PublicX, key.PublicY); !curve.IsOnCurve(x, y) {
+			t.Errorf("%s: P + P = (%x, %x) is not on the curve", curve.Params().Name, x, y)
+		}
+	}
+}
+
+// Fixed key pair on the toy curve, so that tests relying on particular
+// values of k cannot be thrown by an unlucky random key
+func toyKey(t *testing.T, curve elliptic.Curve) Key {
+	t.Helper()
+	var key = Key{Private: big.NewInt(4321), Curve: curve}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+	return key
+}
+
+// Smallest k of the toy curve for which kG has the x-coordinate 0, and so r = 0
+func toyZeroRK(t *testing.T, curve elliptic.Curve) *big.Int {
+	t.Helper()
+	for k := int64(1); k < curve.Params().N.Int64(); k++ {
+		if x, _ := curve.ScalarBaseMult(big.NewInt(k).Bytes()); x.Sign() == 0 {
+			return big.NewInt(k)
+		}
+	}
+	t.Fatal("no point with x = 0 on the toy curve")
+	return nil
+}
+
+func TestSignRetriesZeroR(t *testing.T) {
+	var curve = toyCurve(t)
+	var key = toyKey(t, curve)
+	var zeroR = toyZeroRK(t, curve)
+	var good = big.NewInt(1234)
+
+	if _, _, err := SignWithK(key, testMessageHash[:], zeroR); !errors.Is(err, ErrZeroSignature) {
+		t.Fatalf("SignWithK with r = 0: err = %v, want %v", err, ErrZeroSignature)
+	}
+
+	// A source that first yields the k giving r = 0, then a good one
+	var stubbed bytes.Buffer
+	stubbed.Write(candidateBuffer(curve, new(big.Int).Sub(zeroR, big.NewInt(1))))
+	stubbed.Write(candidateBuffer(curve, new(big.Int).Sub(good, big.NewInt(1))))
+
+	r, s, _, _, err := signPoint(context.Background(), &stubbed, key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantR, wantS, err := SignWithK(key, testMessageHash[:], good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		t.Fatalf("(r, s) = (%v, %v), want (%v, %v) of the second k", r, s, wantR, wantS)
+	}
+	if stubbed.Len() != 0 {
+		t.Fatalf("%d bytes of the source left unread", stubbed.Len())
+	}
+	if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+		t.Fatal("signature does not verify")
+	}
+}
+
+func TestSignRetriesZeroS(t *testing.T) {
+	// s = 0 when z = -re mod N, for the key and the r of the first k
+	var curve = toyCurve(t)
+	var key = toyKey(t, curve)
+	var n = curve.Params().N
+	var bad, good = big.NewInt(100), big.NewInt(200)
+
+	x, _ := curve.ScalarBaseMult(bad.Bytes())
+	var z = new(big.Int).Mul(modN(x, curve), key.Private)
+	z.Neg(z).Mod(z, n)
+	var messageHash = z.FillBytes(make([]byte, 2))
+	if _, _, err := SignWithK(key, messageHash, bad); !errors.Is(err, ErrZeroSignature) {
+		t.Fatalf("SignWithK with s = 0: err = %v, want %v", err, ErrZeroSignature)
+	}
+
+	var stubbed bytes.Buffer
+	stubbed.Write(candidateBuffer(curve, new(big.Int).Sub(bad, big.NewInt(1))))
+	stubbed.Write(candidateBuffer(curve, new(big.Int).Sub(good, big.NewInt(1))))
+
+	r, s, _, _, err := signPoint(context.Background(), &stubbed, key, messageHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantR, wantS, err := SignWithK(key, messageHash, good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		t.Fatalf("(r, s) = (%v, %v), want (%v, %v) of the second k", r, s, wantR, wantS)
+	}
+}
+
+func TestSignRetriesExhausted(t *testing.T) {
+	// Every k giving r = 0, so that signing gives up after maxSignAttempts
+	var curve = toyCurve(t)
+	var key = toyKey(t, curve)
+	var buffer = candidateBuffer(curve, new(big.Int).Sub(toyZeroRK(t, curve), big.NewInt(1)))
+
+	var stubbed bytes.Buffer
+	for i := 0; i < maxSignAttempts; i++ {
+		stubbed.Write(buffer)
+	}
+	if _, _, _, _, err := signPoint(context.Background(), &stubbed, key, testMessageHash[:]); !errors.Is(err, ErrZeroSignature) {
+		t.Fatalf("err = %v, want %v", err, ErrZeroSignature)
+	}
+	if stubbed.Len() != 0 {
+		t.Fatalf("%d bytes of the source left unread", stubbed.Len())
+	}
+}
@@ -0,0 +1,27 @@
+package ecdsaplay
+
+import "errors"
+
+// Errors returned by this package, possibly wrapped with further detail;
+// callers should compare against them using errors.Is
+var (
+	ErrInvalidK          = errors.New("Error: Invalid k")
+	ErrPointNotOnCurve   = errors.New("Error: Point not on the curve")
+	ErrZeroSignature     = errors.New("Error: Invalid signature, r = 0 or s = 0")
+	ErrInvalidHashLength = errors.New("Error: Invalid message hash length")
+	ErrHashTooLong       = errors.New("Error: Invalid message hash, longer than any digest")
+
+	ErrROutOfRange         = errors.New("Error: Invalid signature, r outside of [1, N-1]")
+	ErrSOutOfRange         = errors.New("Error: Invalid signature, s outside of [1, N-1]")
+	ErrRecomputedRMismatch = errors.New("Error: Invalid signature, calculated r does not match")
+	ErrInfinityPoint       = errors.New("Error: Invalid signature, uG + vP is the point at infinity")
+	ErrCrosscheckMismatch  = errors.New("Error: uG + vP differs between the two calculations")
+	ErrIdentityPublicKey   = errors.New("Error: Invalid public key, point at infinity")
+
+	ErrKeyMismatch       = errors.New("Error: Invalid key, public key does not match private key")
+	ErrUnsupportedCurve  = errors.New("Error: Unsupported curve")
+	ErrInvalidPrivateKey = errors.New("Error: Invalid private key")
+	ErrEntropyFailure    = errors.New("Error: Random number generator failed the health check")
+
+	ErrVanityTimeout = errors.New("Error: No public key with the requested prefix")
+)
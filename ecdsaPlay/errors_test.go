@@ -0,0 +1,134 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	var curve = elliptic.P256()
+	var key = generateKey(t, curve)
+	var k = big.NewInt(12345)
+	var tooLong = make([]byte, MaxMessageHashSize+1)
+
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offCurveY = new(big.Int).Add(key.PublicY, big.NewInt(1))
+
+	var tests = []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"GeneratePreMessageSecretFrom stuck at zero", func() error {
+			_, err := GeneratePreMessageSecretFrom(bytes.NewReader(make([]byte, 1<<16)), curve)
+			return err
+		}, ErrEntropyFailure},
+		{"GeneratePreMessageSecretFrom exhausted", func() error {
+			_, err := GeneratePreMessageSecretFrom(bytes.NewReader(nil), curve)
+			return err
+		}, io.EOF},
+
+		// Every way of signing rejects an empty hash, and one longer than
+		// any digest
+		{"Sign empty hash", func() error { _, _, err := Sign(key, nil); return err }, ErrInvalidHashLength},
+		{"SignWithK empty hash", func() error { _, _, err := SignWithK(key, []byte{}, k); return err }, ErrInvalidHashLength},
+		{"SignDeterministic empty hash", func() error { _, _, err := SignDeterministic(key, nil); return err }, ErrInvalidHashLength},
+		{"SignDeterministicWithEntropy empty hash", func() error {
+			_, _, err := SignDeterministicWithEntropy(key, nil, []byte("extra"))
+			return err
+		}, ErrInvalidHashLength},
+		{"SignBlinded empty hash", func() error { _, err := SignBlinded(key, nil); return err }, ErrInvalidHashLength},
+		{"SignComponents empty hash", func() error { _, err := SignComponents(key, nil, k); return err }, ErrInvalidHashLength},
+		{"Sign hash too long", func() error { _, _, err := Sign(key, tooLong); return err }, ErrHashTooLong},
+		{"SignWithK hash too long", func() error { _, _, err := SignWithK(key, tooLong, k); return err }, ErrHashTooLong},
+		{"SignDeterministic hash too long", func() error { _, _, err := SignDeterministic(key, tooLong); return err }, ErrHashTooLong},
+		{"SignBlinded hash too long", func() error { _, err := SignBlinded(key, tooLong); return err }, ErrHashTooLong},
+
+		{"SignWithK k = N", func() error {
+			_, _, err := SignWithK(key, testMessageHash[:], curve.Params().N)
+			return err
+		}, ErrInvalidK},
+		{"Sign without private key", func() error {
+			_, _, err := Sign(Key{PublicX: key.PublicX, PublicY: key.PublicY, Curve: curve}, testMessageHash[:])
+			return err
+		}, ErrInvalidPrivateKey},
+
+		{"VerifyDetailed off-curve key", func() error {
+			_, err := VerifyDetailed(r, s, key.PublicX, offCurveY, curve, testMessageHash[:])
+			return err
+		}, ErrPointNotOnCurve},
+		{"VerifyDetailed hash too long", func() error {
+			_, err := VerifyDetailed(r, s, key.PublicX, key.PublicY, curve, tooLong)
+			return err
+		}, ErrHashTooLong},
+	}
+
+	for _, test := range tests {
+		if err := test.call(); !errors.Is(err, test.want) {
+			t.Errorf("%s: err = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestVerifyEmptyHash(t *testing.T) {
+	// An empty hash cannot be signed, and verification of one fails on the
+	// recomputed r rather than on its length
+	var key = generateKey(t, elliptic.P256())
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyDetailed(r, s, key.PublicX, key.PublicY, key.Curve, nil); !errors.Is(err, ErrRecomputedRMismatch) {
+		t.Errorf("VerifyDetailed of an empty hash: err = %v, want %v", err, ErrRecomputedRMismatch)
+	}
+}
+
+func TestNegativePrivateKey(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var valid = generateKey(t, curve)
+
+		// Each private scalar is outside of [1, N-1], with the public key of a
+		// valid key so that only the scalar is at fault
+		var scalars = []struct {
+			name string
+			d    *big.Int
+		}{
+			{"-1", big.NewInt(-1)},
+			{"-d", new(big.Int).Neg(valid.Private)},
+			{"-N", new(big.Int).Neg(n)},
+			{"N", new(big.Int).Set(n)},
+			{"N + d", new(big.Int).Add(n, valid.Private)},
+		}
+
+		for _, scalar := range scalars {
+			var key = Key{Private: scalar.d, PublicX: valid.PublicX, PublicY: valid.PublicY, Curve: curve}
+
+			var calls = []struct {
+				name string
+				call func() error
+			}{
+				{"Validate", key.Validate},
+				{"NewKeyFromScalar", func() error { _, err := NewKeyFromScalar(curve, scalar.d); return err }},
+				{"Sign", func() error { _, _, err := Sign(key, testMessageHash[:]); return err }},
+				{"SignWithK", func() error { _, _, err := SignWithK(key, testMessageHash[:], big.NewInt(12345)); return err }},
+				{"SignDeterministic", func() error { _, _, err := SignDeterministic(key, testMessageHash[:]); return err }},
+				{"SignBlinded", func() error { _, err := SignBlinded(key, testMessageHash[:]); return err }},
+				{"Key.Sign", func() error { _, err := key.Sign(nil, testMessageHash[:], nil); return err }},
+			}
+
+			for _, call := range calls {
+				if err := call.call(); !errors.Is(err, ErrInvalidPrivateKey) {
+					t.Errorf("%s d = %s: %s err = %v, want %v", curve.Params().Name, scalar.name, call.name, err, ErrInvalidPrivateKey)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ecdsaplay
+
+import "errors"
+
+// Byte length of an Ethereum signature [R || S || V]
+const ethereumSignatureLen = 65
+
+// Offset added to V by legacy Ethereum transactions and eth_sign, giving 27/28
+const ethereumLegacyVOffset = 27
+
+// Signs the message hash with a secp256k1 key and returns the 65 byte Ethereum
+// signature [R || S || V]; where, R and S are 32 bytes each, S is low-s
+// normalized and V is the recovery id (0 or 1) accepted by RecoverPublicKey.
+// With legacyV set, V is offset by 27 as expected by eth_sign and pre EIP-155
+// transactions. As V cannot express an x-coordinate of R at or above N, such
+// signatures are discarded and a fresh k is selected
+func SignEthereum(key Key, messageHash []byte, legacyV bool) ([]byte, error) {
+	if key.Curve != Secp256k1() {
+		return nil, errors.New("Error: Invalid key, Ethereum signatures require secp256k1")
+	}
+
+	for attempt := 0; attempt < maxSignAttempts; attempt++ {
+		r, s, recid, err := SignRecoverable(key, messageHash)
+		if err != nil {
+			return nil, err
+		}
+		if recid&2 != 0 {
+			continue
+		}
+
+		var v = byte(recid)
+		if legacyV {
+			v += ethereumLegacyVOffset
+		}
+
+		return append(EncodeSignatureFixed(Signature{R: r, S: s}, key.Curve), v), nil
+	}
+
+	return nil, errors.New("Error: No signature with a recovery id expressible as V")
+}
+
+// Decodes a 65 byte Ethereum signature [R || S || V] into the signature and
+// its recovery id for RecoverPublicKey. V may be 0/1 or the legacy 27/28
+func DecodeEthereumSignature(sig []byte) (Signature, int, error) {
+	if len(sig) != ethereumSignatureLen {
+		return Signature{}, 0, errors.New("Error: Invalid Ethereum signature, must be 65 bytes")
+	}
+
+	var v = int(sig[64])
+	if v >= ethereumLegacyVOffset {
+		v -= ethereumLegacyVOffset
+	}
+	if v != 0 && v != 1 {
+		return Signature{}, 0, errors.New("Error: Invalid Ethereum signature, V must be 0, 1, 27 or 28")
+	}
+
+	signature, err := DecodeSignatureFixed(sig[:64], Secp256k1())
+	if err != nil {
+		return Signature{}, 0, err
+	}
+	return signature, v, nil
+}
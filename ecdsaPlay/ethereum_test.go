@@ -0,0 +1,91 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+)
+
+func TestSignEthereumRecover(t *testing.T) {
+	var curve = Secp256k1()
+	var key = generateKey(t, curve)
+
+	for _, legacyV := range []bool{false, true} {
+		for i := 0; i < 20; i++ {
+			sig, err := SignEthereum(key, testMessageHash[:], legacyV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != 65 {
+				t.Fatalf("legacyV %v: len(SignEthereum) = %d, want 65", legacyV, len(sig))
+			}
+			if v := sig[64]; (legacyV && v != 27 && v != 28) || (!legacyV && v != 0 && v != 1) {
+				t.Fatalf("legacyV %v: V = %d", legacyV, v)
+			}
+
+			signature, recid, err := DecodeEthereumSignature(sig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if NormalizeS(signature.S, curve).Cmp(signature.S) != 0 {
+				t.Fatalf("legacyV %v: SignEthereum gave a high s", legacyV)
+			}
+			x, y, err := RecoverPublicKey(signature, recid, curve, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+				t.Fatalf("legacyV %v: RecoverPublicKey = (%x, %x), want (%x, %x)", legacyV, x, y, key.PublicX, key.PublicY)
+			}
+		}
+	}
+}
+
+func TestSignEthereumRejectsCurve(t *testing.T) {
+	if sig, err := SignEthereum(generateKey(t, elliptic.P256()), testMessageHash[:], false); err == nil {
+		t.Fatalf("SignEthereum with a P-256 key = %x, want an error", sig)
+	}
+}
+
+func TestDecodeEthereumSignatureInvalid(t *testing.T) {
+	sig, err := SignEthereum(generateKey(t, Secp256k1()), testMessageHash[:], false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var withV = func(v byte) []byte {
+		return append(append([]byte{}, sig[:64]...), v)
+	}
+	var tests = []struct {
+		name string
+		sig  []byte
+	}{
+		{"empty", nil},
+		{"64 bytes", sig[:64]},
+		{"66 bytes", append(withV(0), 0)},
+		{"V = 2", withV(2)},
+		{"V = 26", withV(26)},
+		{"V = 29", withV(29)},
+	}
+
+	for _, test := range tests {
+		if signature, recid, err := DecodeEthereumSignature(test.sig); err == nil {
+			t.Errorf("%s: DecodeEthereumSignature(%x) = (%v, %d), want an error", test.name, test.sig, signature, recid)
+		}
+	}
+
+	// As DecodeSignatureFixed, the decoder checks the length only and leaves
+	// the range of r and s to RecoverPublicKey
+	var zeroR = withV(0)
+	copy(zeroR[:32], make([]byte, 32))
+	var highS = append(append(append([]byte{}, sig[:32]...), bytes.Repeat([]byte{0xff}, 32)...), 0)
+
+	for _, b := range [][]byte{zeroR, highS} {
+		signature, recid, err := DecodeEthereumSignature(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x, y, err := RecoverPublicKey(signature, recid, Secp256k1(), testMessageHash[:]); err == nil {
+			t.Errorf("RecoverPublicKey(%v) = (%x, %x), want an error", signature, x, y)
+		}
+	}
+}
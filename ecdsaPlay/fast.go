@@ -0,0 +1,130 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Window width of the w-NAF scalar multiplication, i.e. the table holds
+// the 2^(w-2) odd multiples P, 3P, ..., (2^(w-1) - 1)P
+const wnafWidth = 4
+
+// Verification as per Verify, calculating uG + vP in a single pass with
+// doubleScalarMult instead of two independent scalar multiplications. The
+// gain applies to the curves implemented by this package such as Secp256k1;
+// the NIST curves of crypto/elliptic use optimized field arithmetic that
+// remains faster than this package's big.Int based point arithmetic
+func VerifyFast(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte) bool {
+	valid, _ := verify(r, s, nil, publicKeyX, publicKeyY, curve, messageHash, doubleScalarMult)
+	return valid
+}
+
+// Calculates uG + vP using Shamir's trick: with the joint table G, P and
+// G + P, the bits of u and v are scanned together from the most significant
+// one, doubling once per bit and adding G, P or G + P depending on which of
+// the two bits are set. This takes a single run of doublings instead of one
+// per scalar
+func doubleScalarMult(curve elliptic.Curve, u, v, Px, Py *big.Int) (x, y *big.Int) {
+	var arithmetic = jacobianArithmetic(curve)
+	var g = arithmetic.toJacobian(curve.Params().Gx, curve.Params().Gy)
+	var p = arithmetic.toJacobian(Px, Py)
+	var table = [4]jacobianPoint{{}, g, p, arithmetic.addJacobian(g, p)}
+
+	var bits = u.BitLen()
+	if v.BitLen() > bits {
+		bits = v.BitLen()
+	}
+
+	var result = jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	for i := bits - 1; i >= 0; i-- {
+		result = arithmetic.doubleJacobian(result)
+
+		if index := u.Bit(i) | v.Bit(i)<<1; index != 0 {
+			result = arithmetic.addJacobian(result, table[index])
+		}
+	}
+
+	return arithmetic.toAffine(result)
+}
+
+// Calculates kP from the w-NAF digits of k. Every non-zero digit is odd and
+// within (-2^(w-1), 2^(w-1)), and any two non-zero digits are at least w
+// positions apart, so on average only one in w+1 doublings is followed by an
+// addition of a precomputed odd multiple of P (or its negation, which costs
+// nothing). The arithmetic is performed in Jacobian coordinates throughout,
+// with a single inversion at the end. VerifyFast relies on doubleScalarMult
+// instead; this is retained as a reference for comparison with ScalarMult by
+// BenchmarkScalarMultWNAF
+func scalarMultWNAF(curve elliptic.Curve, Px, Py, k *big.Int) (x, y *big.Int) {
+	var arithmetic = jacobianArithmetic(curve)
+	var table = arithmetic.oddMultiples(arithmetic.toJacobian(Px, Py), 1<<(wnafWidth-2))
+	var digits = wnaf(k, wnafWidth)
+
+	var result = jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	for i := len(digits) - 1; i >= 0; i-- {
+		result = arithmetic.doubleJacobian(result)
+
+		switch d := digits[i]; {
+		case d > 0:
+			result = arithmetic.addJacobian(result, table[d/2])
+		case d < 0:
+			result = arithmetic.addJacobian(result, arithmetic.negateJacobian(table[-d/2]))
+		}
+	}
+
+	return arithmetic.toAffine(result)
+}
+
+// w-NAF digits of k, least significant first: while k > 0, an odd k yields
+// the digit d = k mods 2^w (the residue within (-2^(w-1), 2^(w-1))) and k is
+// replaced by k - d, an even k yields 0, and k is halved
+func wnaf(k *big.Int, w uint) []int {
+	var digits []int
+	var window = int64(1) << w
+	var remaining = new(big.Int).Set(k)
+	var mask = big.NewInt(window - 1)
+
+	for remaining.Sign() > 0 {
+		var d int64
+		if remaining.Bit(0) == 1 {
+			d = new(big.Int).And(remaining, mask).Int64()
+			if d >= window/2 {
+				d -= window
+			}
+			remaining.Sub(remaining, big.NewInt(d))
+		}
+		digits = append(digits, int(d))
+		remaining.Rsh(remaining, 1)
+	}
+
+	return digits
+}
+
+// P, 3P, 5P, ... as count Jacobian points
+func (curve *weierstrassCurve) oddMultiples(point jacobianPoint, count int) []jacobianPoint {
+	var table = make([]jacobianPoint, count)
+	table[0] = point
+
+	var double = curve.doubleJacobian(point)
+	for i := 1; i < count; i++ {
+		table[i] = curve.addJacobian(table[i-1], double)
+	}
+
+	return table
+}
+
+// -(X, Y, Z) = (X, -Y, Z)
+func (curve *weierstrassCurve) negateJacobian(point jacobianPoint) jacobianPoint {
+	var y = new(big.Int).Neg(point.y)
+	y.Mod(y, curve.params.P)
+	return jacobianPoint{point.x, y, point.z}
+}
+
+// Jacobian point arithmetic of this package for any curve: the curve itself
+// when implemented here, or its parameters along with a = -3 otherwise
+func jacobianArithmetic(curve elliptic.Curve) *weierstrassCurve {
+	if c, ok := curve.(*weierstrassCurve); ok {
+		return c
+	}
+	return &weierstrassCurve{params: curve.Params(), a: curveA(curve)}
+}
@@ -0,0 +1,122 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestWNAF(t *testing.T) {
+	var n = nistCurves[3].Params().N
+	var values = []*big.Int{big.NewInt(1), big.NewInt(7), big.NewInt(8), big.NewInt(0xffff), new(big.Int).Sub(n, big.NewInt(1))}
+	for i := 0; i < 100; i++ {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, k)
+	}
+
+	for _, k := range values {
+		var digits = wnaf(k, wnafWidth)
+
+		// k = sum of d_i 2^i, with every non-zero digit odd, within
+		// (-2^(w-1), 2^(w-1)) and followed by at least w-1 zeros
+		var sum = new(big.Int)
+		var lastNonZero = -wnafWidth
+		for i, d := range digits {
+			if d == 0 {
+				continue
+			}
+			if d%2 == 0 || d <= -(1<<(wnafWidth-1)) || d >= 1<<(wnafWidth-1) {
+				t.Fatalf("wnaf(%x): invalid digit %d", k, d)
+			}
+			if i-lastNonZero < wnafWidth {
+				t.Fatalf("wnaf(%x): non-zero digits %d and %d closer than %d", k, lastNonZero, i, wnafWidth)
+			}
+			lastNonZero = i
+			sum.Add(sum, new(big.Int).Lsh(big.NewInt(int64(d)), uint(i)))
+		}
+		if sum.Cmp(k) != 0 {
+			t.Fatalf("wnaf(%x) sums to %x", k, sum)
+		}
+	}
+}
+
+func TestScalarMultWNAF(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var key = generateKey(t, curve)
+
+		var scalars = []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(15), big.NewInt(16), new(big.Int).Sub(n, big.NewInt(1))}
+		for i := 0; i < 50; i++ {
+			k, err := GeneratePreMessageSecret(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scalars = append(scalars, k)
+		}
+
+		for _, k := range scalars {
+			x, y := scalarMultWNAF(curve, key.PublicX, key.PublicY, k)
+			wantX, wantY := curve.ScalarMult(key.PublicX, key.PublicY, k.Bytes())
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Fatalf("%s: scalarMultWNAF(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, k, x, y, wantX, wantY)
+			}
+		}
+
+		// N*P is the point at infinity
+		if x, y := scalarMultWNAF(curve, key.PublicX, key.PublicY, n); x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("%s: scalarMultWNAF(N) = (%x, %x), want (0, 0)", curve.Params().Name, x, y)
+		}
+	}
+}
+
+func TestDoubleScalarMult(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		for i := 0; i < 20; i++ {
+			u, _ := GeneratePreMessageSecret(curve)
+			v, _ := GeneratePreMessageSecret(curve)
+			x, y := doubleScalarMult(curve, u, v, key.PublicX, key.PublicY)
+			wantX, wantY := scalarMultCombination(curve, u, v, key.PublicX, key.PublicY)
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Fatalf("%s: doubleScalarMult(%x, %x) = (%x, %x), want (%x, %x)", curve.Params().Name, u, v, x, y, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestVerifyFastMatchesVerify(t *testing.T) {
+	// 1000 random signatures over P-256 and secp256k1, half of them altered
+	// so that both outcomes are compared
+	for _, test := range []struct {
+		curve elliptic.Curve
+		count int
+	}{{elliptic.P256(), 800}, {Secp256k1(), 200}} {
+		var keys = []Key{generateKey(t, test.curve), generateKey(t, test.curve)}
+		for i := 0; i < test.count; i++ {
+			var key = keys[i%2]
+			var messageHash = sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+			r, s, err := Sign(key, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i%4 == 1 {
+				s.Add(s, big.NewInt(1))
+			} else if i%4 == 3 {
+				key = keys[(i+1)%2]
+			}
+
+			var want = Verify(r, s, key.PublicX, key.PublicY, test.curve, messageHash[:])
+			if want != (i%2 == 0) {
+				t.Fatalf("%s: Verify = %v for signature %d", test.curve.Params().Name, want, i)
+			}
+			if got := VerifyFast(r, s, key.PublicX, key.PublicY, test.curve, messageHash[:]); got != want {
+				t.Fatalf("%s: VerifyFast = %v, Verify = %v for signature %d", test.curve.Params().Name, got, want, i)
+			}
+		}
+	}
+}
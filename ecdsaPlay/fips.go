@@ -0,0 +1,70 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Signing and verification restricted to what Federal Information Processing
+// Standard Publication (FIPS PUB 186-4) Digital Signature Standard (DSS)
+// issued July 2013 permits: the approved curves P-224, P-256, P-384 and P-521
+// only, a message hash at least as strong as the curve, the leftmost
+// N.BitLen() bits of the hash as z (section 6.4), and k within [1, N-1]
+// generated as per B.5.1
+type FIPSSigner struct {
+	key Key
+}
+
+// Returns a FIPSSigner for key, which must be on an approved curve
+func NewFIPSSigner(key Key) (*FIPSSigner, error) {
+	if !isFIPSCurve(key.Curve) {
+		return nil, errors.New("Error: Unsupported curve, not approved by FIPS 186-4")
+	}
+	if err := checkPrivateKey(key); err != nil {
+		return nil, err
+	}
+	return &FIPSSigner{key: key}, nil
+}
+
+// Signature = (r, s) as per Sign, once the message hash is checked to be long
+// enough for the curve
+func (f *FIPSSigner) Sign(messageHash []byte) (r, s *big.Int, err error) {
+	if err = checkFIPSHash(f.key.Curve, messageHash); err != nil {
+		return nil, nil, err
+	}
+	return Sign(f.key, messageHash)
+}
+
+// Verification as per Verify against the signer's own public key, rejecting
+// message hashes that are too short for the curve
+func (f *FIPSSigner) Verify(r, s *big.Int, messageHash []byte) bool {
+	if checkFIPSHash(f.key.Curve, messageHash) != nil {
+		return false
+	}
+	return Verify(r, s, f.key.PublicX, f.key.PublicY, f.key.Curve, messageHash)
+}
+
+// P-224, P-256, P-384 and P-521 of appendix D.1.2 of FIPS 186-4
+func isFIPSCurve(curve elliptic.Curve) bool {
+	switch curve {
+	case elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521():
+		return true
+	}
+	return false
+}
+
+// The security strength of the hash, half its length, must be at least that
+// of the curve, half of N.BitLen() (capped at 256 bits for P-521), as per
+// NIST Special Publication 800-57
+func checkFIPSHash(curve elliptic.Curve, messageHash []byte) error {
+	var requiredBits = curve.Params().N.BitLen()
+	if requiredBits > 512 {
+		requiredBits = 512
+	}
+	if len(messageHash)*8 < requiredBits {
+		return fmt.Errorf("%w, too short for the curve", ErrInvalidHashLength)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package ecdsaplay
+
+import (
+	"bufio"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Records of a NIST CAVP response file, each a map of the "key = value"
+// lines between blank lines, restricted to the section of the given header
+func readCAVP(t *testing.T, name, section string) []map[string]string {
+	t.Helper()
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []map[string]string
+	var record map[string]string
+	var inSection bool
+	var scanner = bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "["):
+			inSection = line == section
+		case line == "":
+			record = nil
+		case inSection:
+			var parts = strings.SplitN(line, " = ", 2)
+			if len(parts) != 2 {
+				t.Fatalf("%s: invalid line %q", name, line)
+			}
+			if record == nil {
+				record = make(map[string]string)
+				records = append(records, record)
+			}
+			record[parts[0]] = parts[1]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 {
+		t.Fatalf("%s: no records in %s", name, section)
+	}
+	return records
+}
+
+func TestCAVPSigGenP256SHA256(t *testing.T) {
+	var curve = elliptic.P256()
+	for i, record := range readCAVP(t, "testdata/SigGen_P256_SHA256.rsp", "[P-256,SHA-256]") {
+		var key = Key{Private: hexInt(record["d"]), Curve: curve}
+		key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+		if key.PublicX.Cmp(hexInt(record["Qx"])) != 0 || key.PublicY.Cmp(hexInt(record["Qy"])) != 0 {
+			t.Fatalf("case %d: public key (%x, %x), want (%s, %s)", i, key.PublicX, key.PublicY, record["Qx"], record["Qy"])
+		}
+		var messageHash = sha256.Sum256(hexBytes(record["Msg"]))
+
+		r, s, err := SignWithK(key, messageHash[:], hexInt(record["k"]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(hexInt(record["R"])) != 0 || s.Cmp(hexInt(record["S"])) != 0 {
+			t.Errorf("case %d: (r, s) = (%x, %x), want (%s, %s)", i, r, s, record["R"], record["S"])
+		}
+
+		signer, err := NewFIPSSigner(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !signer.Verify(r, s, messageHash[:]) {
+			t.Errorf("case %d: FIPSSigner rejected the known signature", i)
+		}
+		r, s, err = signer.Sign(messageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !signer.Verify(r, s, messageHash[:]) {
+			t.Errorf("case %d: FIPSSigner rejected its own signature", i)
+		}
+	}
+}
+
+func TestCAVPSigVerP256SHA256(t *testing.T) {
+	var curve = elliptic.P256()
+	for i, record := range readCAVP(t, "testdata/SigVer_P256_SHA256.rsp", "[P-256,SHA-256]") {
+		var messageHash = sha256.Sum256(hexBytes(record["Msg"]))
+		var want = strings.HasPrefix(record["Result"], "P")
+
+		var got = Verify(hexInt(record["R"]), hexInt(record["S"]), hexInt(record["Qx"]), hexInt(record["Qy"]), curve, messageHash[:])
+		if got != want {
+			t.Errorf("case %d: Verify = %v, want %v for %q", i, got, want, record["Result"])
+		}
+	}
+}
+
+func TestFIPSSignerRejects(t *testing.T) {
+	if _, err := NewFIPSSigner(generateKey(t, Secp256k1())); err == nil {
+		t.Error("NewFIPSSigner accepted a key on secp256k1")
+	}
+	if _, err := NewFIPSSigner(Key{Curve: elliptic.P256()}); !errors.Is(err, ErrInvalidPrivateKey) {
+		t.Errorf("NewFIPSSigner without a private key: err = %v, want %v", err, ErrInvalidPrivateKey)
+	}
+
+	// SHA-224 is too weak for P-256, as is SHA-256 for P-384
+	var tests = []struct {
+		curve   elliptic.Curve
+		hashLen int
+	}{
+		{elliptic.P256(), 28},
+		{elliptic.P384(), 32},
+		{elliptic.P521(), 48},
+	}
+	for _, test := range tests {
+		signer, err := NewFIPSSigner(generateKey(t, test.curve))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := signer.Sign(make([]byte, test.hashLen)); !errors.Is(err, ErrInvalidHashLength) {
+			t.Errorf("%s: Sign of a %d byte hash: err = %v, want %v", test.curve.Params().Name, test.hashLen, err, ErrInvalidHashLength)
+		}
+	}
+}
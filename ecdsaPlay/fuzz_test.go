@@ -0,0 +1,66 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func FuzzVerify(f *testing.F) {
+	var key = generateKey(f, nistCurves[1])
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		f.Fatal(err)
+	}
+	var n = key.Curve.Params().N
+
+	f.Add(uint8(1), r.Bytes(), s.Bytes(), key.PublicX.Bytes(), key.PublicY.Bytes(), testMessageHash[:])
+	f.Add(uint8(1), []byte{}, s.Bytes(), key.PublicX.Bytes(), key.PublicY.Bytes(), testMessageHash[:])
+	f.Add(uint8(1), r.Bytes(), n.Bytes(), key.PublicX.Bytes(), key.PublicY.Bytes(), testMessageHash[:])
+	f.Add(uint8(1), r.Bytes(), s.Bytes(), []byte{}, []byte{}, []byte{})
+	f.Add(uint8(4), []byte{1}, []byte{1}, []byte{1}, []byte{2}, make([]byte, 100))
+
+	var curves = append(nistCurves, Secp256k1())
+	f.Fuzz(func(t *testing.T, curveIndex uint8, r, s, x, y, messageHash []byte) {
+		var curve = curves[int(curveIndex)%len(curves)]
+		var rInt, sInt = new(big.Int).SetBytes(r), new(big.Int).SetBytes(s)
+		var xInt, yInt = new(big.Int).SetBytes(x), new(big.Int).SetBytes(y)
+
+		var valid = Verify(rInt, sInt, xInt, yInt, curve, messageHash)
+		if detailed, err := VerifyDetailed(rInt, sInt, xInt, yInt, curve, messageHash); detailed != valid || (err == nil) != valid {
+			t.Fatalf("VerifyDetailed = (%v, %v), Verify = %v", detailed, err, valid)
+		}
+		if fast := VerifyFast(rInt, sInt, xInt, yInt, curve, messageHash); fast != valid {
+			t.Fatalf("VerifyFast = %v, Verify = %v", fast, valid)
+		}
+	})
+}
+
+func FuzzDecodeSignatureDER(f *testing.F) {
+	// The DER test cases of main.go: minimal, non-canonical and with trailing
+	// junk, along with a signature of SignAndEncode
+	var minimal = []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}
+	f.Add(minimal)
+	f.Add([]byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x02})
+	f.Add(append(append([]byte{}, minimal...), 0xde, 0xad))
+	der, err := SignAndEncode(generateKey(f, nistCurves[3]), testMessageHash[:])
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(der)
+
+	f.Fuzz(func(t *testing.T, der []byte) {
+		r, s, err := DecodeSignatureDER(der)
+		if err != nil {
+			return
+		}
+		// Only DER is accepted, of which there is a single encoding of (r, s)
+		encoded, err := EncodeSignatureDER(r, s)
+		if err != nil {
+			t.Fatalf("EncodeSignatureDER(%x, %x) of decoded %x: %v", r, s, der, err)
+		}
+		if !bytes.Equal(encoded, der) {
+			t.Fatalf("DecodeSignatureDER(%x) = (%x, %x), which encodes as %x", der, r, s, encoded)
+		}
+	})
+}
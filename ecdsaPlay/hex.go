@@ -0,0 +1,86 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+// Encodes the signature as hex(r) || hex(s); where, r and s are each zero
+// padded to the byte size of N, e.g. 128 hex digits for P-256
+func HexSignature(sig Signature, curve elliptic.Curve) string {
+	return hex.EncodeToString(EncodeSignatureFixed(sig, curve))
+}
+
+// Decodes a signature encoded by HexSignature
+func ParseHexSignature(s string, curve elliptic.Curve) (Signature, error) {
+	b, err := decodeFixedHex(s, SignatureByteLen(curve))
+	if err != nil {
+		return Signature{}, err
+	}
+	return DecodeSignatureFixed(b, curve)
+}
+
+// Encodes the public key as hex(x) || hex(y); where, x and y are each zero
+// padded to the byte size of the field, e.g. 128 hex digits for P-256. A
+// public key with a missing curve or coordinate yields ""
+func HexPublicKey(pub PublicKey) string {
+	if pub.Curve == nil || pub.X == nil || pub.Y == nil {
+		return ""
+	}
+
+	var byteLen = (pub.Curve.Params().BitSize + 7) / 8
+	var b = make([]byte, 2*byteLen)
+	pub.X.FillBytes(b[:byteLen])
+	pub.Y.FillBytes(b[byteLen:])
+	return hex.EncodeToString(b)
+}
+
+// Decodes a public key encoded by HexPublicKey, which must lie on the curve
+func ParseHexPublicKey(s string, curve elliptic.Curve) (PublicKey, error) {
+	var byteLen = (curve.Params().BitSize + 7) / 8
+	b, err := decodeFixedHex(s, 2*byteLen)
+	if err != nil {
+		return PublicKey{}, err
+	}
+
+	var pub = PublicKey{X: new(big.Int).SetBytes(b[:byteLen]), Y: new(big.Int).SetBytes(b[byteLen:]), Curve: curve}
+	if err = validatePublicPoint(curve, pub.X, pub.Y); err != nil {
+		return PublicKey{}, err
+	}
+	return pub, nil
+}
+
+// Encodes the private scalar zero padded to the byte size of N, e.g. 64 hex
+// digits for P-256
+func HexPrivateKey(key Key) string {
+	return hex.EncodeToString(key.PrivateBytes())
+}
+
+// Decodes a private scalar encoded by HexPrivateKey and calculates its
+// public key
+func ParseHexPrivateKey(s string, curve elliptic.Curve) (Key, error) {
+	b, err := decodeFixedHex(s, scalarByteLen(curve))
+	if err != nil {
+		return Key{}, err
+	}
+
+	return NewKeyFromBytes(curve, b)
+}
+
+// Byte size of N, i.e. of scalars such as r, s and the private key
+func scalarByteLen(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
+
+// Decodes hex that must be exactly byteLen bytes long
+func decodeFixedHex(s string, byteLen int) ([]byte, error) {
+	if len(s)%2 != 0 {
+		return nil, errors.New("Error: Invalid hex, odd length")
+	}
+	if len(s) != 2*byteLen {
+		return nil, errors.New("Error: Invalid hex, unexpected length")
+	}
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,105 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestHexRoundTrip(t *testing.T) {
+	for _, curve := range SupportedCurves() {
+		var key = generateKey(t, curve)
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var encoded = HexSignature(sig, curve)
+		if len(encoded) != 4*scalarByteLen(curve) {
+			t.Fatalf("%s: HexSignature of %d digits, want %d", curve.Params().Name, len(encoded), 4*scalarByteLen(curve))
+		}
+		decoded, err := ParseHexSignature(encoded, curve)
+		if err != nil || decoded.R.Cmp(sig.R) != 0 || decoded.S.Cmp(sig.S) != 0 {
+			t.Fatalf("%s: ParseHexSignature(%s) = (%v, %v), want %v", curve.Params().Name, encoded, decoded, err, sig)
+		}
+
+		pub, err := ParseHexPublicKey(HexPublicKey(key.PublicKey()), curve)
+		if err != nil || pub.X.Cmp(key.PublicX) != 0 || pub.Y.Cmp(key.PublicY) != 0 {
+			t.Fatalf("%s: public key round trip = (%v, %v)", curve.Params().Name, pub, err)
+		}
+
+		private, err := ParseHexPrivateKey(HexPrivateKey(key), curve)
+		if err != nil || private.Private.Cmp(key.Private) != 0 || private.PublicX.Cmp(key.PublicX) != 0 {
+			t.Fatalf("%s: private key round trip failed: %v", curve.Params().Name, err)
+		}
+	}
+}
+
+func TestHexPadding(t *testing.T) {
+	var curve = elliptic.P256()
+	var key = Key{Private: big.NewInt(0xabc), Curve: curve}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+
+	if got, want := HexPrivateKey(key), strings.Repeat("0", 61)+"abc"; got != want {
+		t.Errorf("HexPrivateKey = %s, want %s", got, want)
+	}
+	var sig = Signature{R: big.NewInt(1), S: big.NewInt(0x1ff)}
+	if got, want := HexSignature(sig, curve), strings.Repeat("0", 63)+"1"+strings.Repeat("0", 61)+"1ff"; got != want {
+		t.Errorf("HexSignature = %s, want %s", got, want)
+	}
+
+	// Hex of a smaller key sorts below that of a larger one
+	var larger = Key{Private: big.NewInt(0x1000), Curve: curve}
+	larger.PublicX, larger.PublicY = larger.Curve.ScalarBaseMult(larger.Private.Bytes())
+	if HexPrivateKey(key) >= HexPrivateKey(larger) {
+		t.Error("hex of private keys does not sort numerically")
+	}
+}
+
+func TestHexPublicKeyMissing(t *testing.T) {
+	var pub = generateKey(t, elliptic.P256()).PublicKey()
+
+	var tests = []struct {
+		name string
+		pub  PublicKey
+	}{
+		{"empty key", PublicKey{}},
+		{"missing curve", PublicKey{X: pub.X, Y: pub.Y}},
+		{"missing x", PublicKey{Y: pub.Y, Curve: pub.Curve}},
+		{"missing y", PublicKey{X: pub.X, Curve: pub.Curve}},
+	}
+
+	for _, test := range tests {
+		if encoded := HexPublicKey(test.pub); encoded != "" {
+			t.Errorf("%s: HexPublicKey = %s, want \"\"", test.name, encoded)
+		}
+	}
+}
+
+func TestParseHexInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var key = generateKey(t, curve)
+	var pub = HexPublicKey(key.PublicKey())
+	var offCurve = HexPublicKey(PublicKey{X: key.PublicX, Y: new(big.Int).Add(key.PublicY, big.NewInt(1)), Curve: curve})
+	var sig = HexSignature(Signature{R: big.NewInt(1), S: big.NewInt(1)}, curve)
+
+	var tests = []struct {
+		name  string
+		parse func() error
+	}{
+		{"odd length signature", func() error { _, err := ParseHexSignature(sig[1:], curve); return err }},
+		{"short signature", func() error { _, err := ParseHexSignature(sig[2:], curve); return err }},
+		{"non-hex signature", func() error { _, err := ParseHexSignature("zz"+sig[2:], curve); return err }},
+		{"odd length public key", func() error { _, err := ParseHexPublicKey(pub+"0", curve); return err }},
+		{"public key off the curve", func() error { _, err := ParseHexPublicKey(offCurve, curve); return err }},
+		{"odd length private key", func() error { _, err := ParseHexPrivateKey("abc", curve); return err }},
+		{"zero private key", func() error { _, err := ParseHexPrivateKey(strings.Repeat("0", 64), curve); return err }},
+	}
+
+	for _, test := range tests {
+		if err := test.parse(); err == nil {
+			t.Errorf("%s: parsed without an error", test.name)
+		}
+	}
+}
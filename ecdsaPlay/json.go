@@ -0,0 +1,131 @@
+package ecdsaplay
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// JSON representation of a Key; where, the private scalar and the public
+// point are big-endian hex values of the curve's byte size
+type keyJSON struct {
+	Curve   string `json:"curve"`
+	Private string `json:"private,omitempty"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// JSON representation of a Signature as big-endian hex values
+type signatureJSON struct {
+	R string `json:"r"`
+	S string `json:"s"`
+}
+
+// Encodes the key as JSON holding the curve name, the private scalar (if any)
+// and the public point in hex
+func (k Key) MarshalJSON() ([]byte, error) {
+	if k.Curve == nil || k.PublicX == nil || k.PublicY == nil {
+		return nil, errors.New("Error: Invalid key, missing curve or public key")
+	}
+	if _, ok := curveByName(k.Curve.Params().Name); !ok {
+		return nil, errors.New("Error: Unknown curve " + k.Curve.Params().Name)
+	}
+
+	var byteLen = (k.Curve.Params().BitSize + 7) / 8
+	var encoded = keyJSON{
+		Curve: k.Curve.Params().Name,
+		X:     hex.EncodeToString(k.PublicX.FillBytes(make([]byte, byteLen))),
+		Y:     hex.EncodeToString(k.PublicY.FillBytes(make([]byte, byteLen))),
+	}
+	if k.Private != nil {
+		encoded.Private = hex.EncodeToString(k.PrivateBytes())
+	}
+
+	return json.Marshal(encoded)
+}
+
+// Decodes a key from JSON as produced by MarshalJSON. The curve name must be
+// one known to this package, the public point must lie on that curve and the
+// private scalar, if any, must be within [1, N-1] or ErrInvalidPrivateKey is
+// returned
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var decoded keyJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	curve, ok := curveByName(decoded.Curve)
+	if !ok {
+		return errors.New("Error: Unknown curve " + decoded.Curve)
+	}
+
+	x, err := hexToInt(decoded.X)
+	if err != nil {
+		return err
+	}
+	y, err := hexToInt(decoded.Y)
+	if err != nil {
+		return err
+	}
+	if err = validatePublicPoint(curve, x, y); err != nil {
+		return err
+	}
+
+	var private *big.Int
+	if decoded.Private != "" {
+		if private, err = hexToInt(decoded.Private); err != nil {
+			return err
+		}
+	}
+
+	var key = Key{Private: private, PublicX: x, PublicY: y, Curve: curve}
+	if private != nil {
+		if err = checkPrivateKey(key); err != nil {
+			return err
+		}
+	}
+
+	*k = key
+	return nil
+}
+
+// Encodes the signature as JSON holding r and s in hex
+func (sig Signature) MarshalJSON() ([]byte, error) {
+	if sig.R == nil || sig.S == nil {
+		return nil, errors.New("Error: Invalid signature, missing r or s")
+	}
+	return json.Marshal(signatureJSON{R: hex.EncodeToString(sig.R.Bytes()), S: hex.EncodeToString(sig.S.Bytes())})
+}
+
+// Decodes a signature from JSON as produced by MarshalJSON
+func (sig *Signature) UnmarshalJSON(data []byte) error {
+	var decoded signatureJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	r, err := hexToInt(decoded.R)
+	if err != nil {
+		return err
+	}
+	s, err := hexToInt(decoded.S)
+	if err != nil {
+		return err
+	}
+
+	*sig = Signature{R: r, S: s}
+	return nil
+}
+
+// Decodes a non-empty big-endian hex value
+func hexToInt(value string) (*big.Int, error) {
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("Error: Invalid hex, empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,92 @@
+package ecdsaplay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKeyJSONRoundTrip(t *testing.T) {
+	for _, curve := range SupportedCurves() {
+		var key = generateKey(t, curve)
+		for _, original := range []Key{key, {PublicX: key.PublicX, PublicY: key.PublicY, Curve: curve}} {
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded Key
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("%s: Unmarshal(%s): %v", curve.Params().Name, data, err)
+			}
+			if decoded.Curve != curve || decoded.PublicX.Cmp(key.PublicX) != 0 || decoded.PublicY.Cmp(key.PublicY) != 0 {
+				t.Errorf("%s: round trip of %s gave another public key", curve.Params().Name, data)
+			}
+			if (original.Private == nil) != (decoded.Private == nil) ||
+				(original.Private != nil && decoded.Private.Cmp(original.Private) != 0) {
+				t.Errorf("%s: round trip of %s gave another private key", curve.Params().Name, data)
+			}
+		}
+	}
+}
+
+func TestSignatureJSONRoundTrip(t *testing.T) {
+	var key = generateKey(t, nistCurves[1])
+	sig, err := SignV2(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Signature
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.R.Cmp(sig.R) != 0 || decoded.S.Cmp(sig.S) != 0 {
+		t.Fatalf("round trip of %s gave %v, want %v", data, decoded, sig)
+	}
+
+	if _, err := json.Marshal(Signature{R: sig.R}); err == nil {
+		t.Error("Marshal of a signature without s succeeded")
+	}
+}
+
+func TestKeyUnmarshalJSONInvalid(t *testing.T) {
+	var key = generateKey(t, nistCurves[1])
+	var x = fmt.Sprintf("%064x", key.PublicX)
+	var y = fmt.Sprintf("%064x", key.PublicY)
+	var otherY = fmt.Sprintf("%064x", key.PublicY.Bit(0)^1)
+
+	var tests = []struct {
+		name string
+		json string
+		want error
+	}{
+		{"point not on the named curve", `{"curve":"P-256","x":"` + x + `","y":"` + otherY + `"}`, ErrPointNotOnCurve},
+		{"P-256 point named P-384", `{"curve":"P-384","x":"` + x + `","y":"` + y + `"}`, ErrPointNotOnCurve},
+		{"point at infinity", `{"curve":"P-256","x":"00","y":"00"}`, ErrIdentityPublicKey},
+		{"unknown curve", `{"curve":"P-192","x":"` + x + `","y":"` + y + `"}`, nil},
+		{"missing y", `{"curve":"P-256","x":"` + x + `"}`, nil},
+		{"invalid hex", `{"curve":"P-256","x":"` + x + `","y":"zz"}`, nil},
+		{"invalid private hex", `{"curve":"P-256","private":"0x1","x":"` + x + `","y":"` + y + `"}`, nil},
+		{"zero private key", `{"curve":"P-256","private":"00","x":"` + x + `","y":"` + y + `"}`, ErrInvalidPrivateKey},
+		{"private key of N", `{"curve":"P-256","private":"` + fmt.Sprintf("%064x", nistCurves[1].Params().N) + `","x":"` + x + `","y":"` + y + `"}`, ErrInvalidPrivateKey},
+		{"not an object", `"P-256"`, nil},
+	}
+
+	for _, test := range tests {
+		var decoded Key
+		err := json.Unmarshal([]byte(test.json), &decoded)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: Unmarshal = %v, want %v", test.name, err, test.want)
+		}
+	}
+
+	if _, err := json.Marshal(Key{Curve: nistCurves[1]}); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Marshal of a key without a public key = %v, want an error", err)
+	}
+}
@@ -0,0 +1,55 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+)
+
+// Signs the JWS signing input, i.e. ASCII(BASE64URL(header) || '.' ||
+// BASE64URL(payload)), as per section 3.4 of RFC 7518 and returns the
+// BASE64URL encoded signature to append to a JWS or JWT. The signature is
+// the fixed-width r || s of EncodeSignatureFixed rather than DER. Only the
+// pairings of ES256 (P-256, SHA-256), ES384 (P-384, SHA-384) and ES512
+// (P-521, SHA-512) are accepted
+func SignJWS(key Key, signingInput []byte, hashFunc crypto.Hash) (string, error) {
+	if err := checkJWSAlgorithm(key.Curve, hashFunc); err != nil {
+		return "", err
+	}
+
+	sig, err := SignMessage(key, signingInput, hashFunc)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(EncodeSignatureFixed(sig, key.Curve)), nil
+}
+
+// Verifies the BASE64URL encoded JWS signature over the signing input
+// against the public key, with the same curve and hash pairings as SignJWS
+func VerifyJWS(signature string, pub PublicKey, signingInput []byte, hashFunc crypto.Hash) bool {
+	if checkJWSAlgorithm(pub.Curve, hashFunc) != nil {
+		return false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	sig, err := DecodeSignatureFixed(b, pub.Curve)
+	if err != nil {
+		return false
+	}
+	return VerifyMessage(sig, pub, signingInput, hashFunc)
+}
+
+// Rejects curve and hash pairings other than those of ES256, ES384 and ES512
+func checkJWSAlgorithm(curve elliptic.Curve, hashFunc crypto.Hash) error {
+	switch {
+	case curve == elliptic.P256() && hashFunc == crypto.SHA256,
+		curve == elliptic.P384() && hashFunc == crypto.SHA384,
+		curve == elliptic.P521() && hashFunc == crypto.SHA512:
+		return nil
+	}
+	return errors.New("Error: Unsupported JWS algorithm, expected ES256, ES384 or ES512")
+}
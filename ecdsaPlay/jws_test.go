@@ -0,0 +1,124 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"testing"
+)
+
+// Decodes unpadded base64url, failing the test on error
+func base64URLInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func TestVerifyJWSRFC7515(t *testing.T) {
+	// ES256 example of appendix A.3 of RFC 7515, also accepted by Python's
+	// cryptography
+	var signingInput = []byte("eyJhbGciOiJFUzI1NiJ9" + "." +
+		"eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ")
+	var signature = "DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+
+	key, err := NewKeyFromScalar(elliptic.P256(), base64URLInt(t, "jpsQnnGQmL-YBIffH1136cspYG6-0iY7X1fCE9-E9LI"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pub = PublicKey{
+		X:     base64URLInt(t, "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"),
+		Y:     base64URLInt(t, "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"),
+		Curve: elliptic.P256(),
+	}
+	if key.PublicX.Cmp(pub.X) != 0 || key.PublicY.Cmp(pub.Y) != 0 {
+		t.Fatalf("public key of d = (%x, %x), want (%x, %x) of the JWK", key.PublicX, key.PublicY, pub.X, pub.Y)
+	}
+
+	if !VerifyJWS(signature, pub, signingInput, crypto.SHA256) {
+		t.Fatal("VerifyJWS rejected the signature of RFC 7515")
+	}
+
+	var tampered = append([]byte{}, signingInput...)
+	tampered[len(tampered)-1] ^= 1
+	var tests = []struct {
+		name      string
+		signature string
+		input     []byte
+		hashFunc  crypto.Hash
+	}{
+		{"tampered input", signature, tampered, crypto.SHA256},
+		{"ES384 pairing", signature, signingInput, crypto.SHA384},
+		{"padded base64url", signature + "==", signingInput, crypto.SHA256},
+		{"base64 alphabet", "DtEhU3ljbEg8L38VWAfUAqOyKAM6+Xx+F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3+Kg6NU1Q", signingInput, crypto.SHA256},
+		{"truncated", signature[:len(signature)-2], signingInput, crypto.SHA256},
+	}
+	for _, test := range tests {
+		if VerifyJWS(test.signature, pub, test.input, test.hashFunc) {
+			t.Errorf("%s: VerifyJWS accepted the signature", test.name)
+		}
+	}
+
+	own, err := SignJWS(key, signingInput, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(own) != 86 || !VerifyJWS(own, pub, signingInput, crypto.SHA256) {
+		t.Errorf("SignJWS = %q, want 86 characters that verify", own)
+	}
+}
+
+func TestJWSInterop(t *testing.T) {
+	var tests = []struct {
+		curve    elliptic.Curve
+		hashFunc crypto.Hash
+	}{
+		{elliptic.P256(), crypto.SHA256},
+		{elliptic.P384(), crypto.SHA384},
+		{elliptic.P521(), crypto.SHA512},
+	}
+	var signingInput = []byte("eyJhbGciOiJFUzI1NiJ9.eyJzdWIiOiJuZW8ifQ")
+
+	for _, test := range tests {
+		var key = generateKey(t, test.curve)
+		var pub = key.PublicKey()
+
+		// A signature of crypto/ecdsa in the fixed-width form of RFC 7518
+		var h = test.hashFunc.New()
+		h.Write(signingInput)
+		r, s, err := ecdsa.Sign(rand.Reader, key.ecdsaPrivateKey(), h.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var theirs = base64.RawURLEncoding.EncodeToString(EncodeSignatureFixed(Signature{R: r, S: s}, test.curve))
+		if !VerifyJWS(theirs, pub, signingInput, test.hashFunc) {
+			t.Errorf("%s: VerifyJWS rejected the signature of crypto/ecdsa", test.curve.Params().Name)
+		}
+
+		ours, err := SignJWS(key, signingInput, test.hashFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := base64.RawURLEncoding.DecodeString(ours)
+		if err != nil || len(b) != SignatureByteLen(test.curve) {
+			t.Fatalf("%s: SignJWS = %q, want %d bytes of base64url", test.curve.Params().Name, ours, SignatureByteLen(test.curve))
+		}
+		var byteLen = len(b) / 2
+		if !ecdsa.Verify(&key.ecdsaPrivateKey().PublicKey, h.Sum(nil), new(big.Int).SetBytes(b[:byteLen]), new(big.Int).SetBytes(b[byteLen:])) {
+			t.Errorf("%s: ecdsa.Verify rejected the signature of SignJWS", test.curve.Params().Name)
+		}
+	}
+
+	// Mismatched curve and hash pairings are rejected
+	if _, err := SignJWS(generateKey(t, elliptic.P256()), signingInput, crypto.SHA512); err == nil {
+		t.Error("SignJWS accepted P-256 with SHA-512")
+	}
+	if _, err := SignJWS(generateKey(t, Secp256k1()), signingInput, crypto.SHA256); err == nil {
+		t.Error("SignJWS accepted secp256k1 with SHA-256")
+	}
+}
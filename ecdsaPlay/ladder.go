@@ -0,0 +1,52 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Returns curve with ScalarMult and ScalarBaseMult performed by
+// scalarMultLadder, for use anywhere a curve is accepted, e.g.
+// GeneratePrivatePublicKeyPair(LadderCurve(elliptic.P256())). The result
+// shares the parameters of curve but performs all of its point arithmetic
+// with this package's Jacobian formulas, so keys and signatures are
+// interchangeable with those of curve itself
+func LadderCurve(curve elliptic.Curve) elliptic.Curve {
+	return &weierstrassCurve{params: curve.Params(), a: curveA(curve), cofactor: curveCofactor(curve), ladder: true}
+}
+
+// Calculates kP with the Montgomery ladder: the pair (R0, R1) starts as
+// (0, P) and keeps the invariant R1 = R0 + P while scanning the bits of k from
+// the most significant one. A bit of 0 sets (R0, R1) = (2R0, R0 + R1) and a
+// bit of 1 sets (R0, R1) = (R0 + R1, 2R1), so every bit costs exactly one
+// addition and one doubling whatever its value. The bits scanned are always
+// those of N.BitLen() (or more for a larger k), so the sequence of point
+// operations does not depend on the scalar either.
+//
+// This is the structure that makes the ladder constant-time in constant-time
+// field arithmetic. Here the choice between the two branches and the big.Int
+// arithmetic are still variable-time, as is the addition to the point at
+// infinity while R0 = 0 over the leading zero bits of k
+func scalarMultLadder(curve elliptic.Curve, Px, Py, k *big.Int) (x, y *big.Int) {
+	var arithmetic = jacobianArithmetic(curve)
+
+	var bits = curve.Params().N.BitLen()
+	if k.BitLen() > bits {
+		bits = k.BitLen()
+	}
+
+	var r0 = jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	var r1 = arithmetic.toJacobian(Px, Py)
+
+	for i := bits - 1; i >= 0; i-- {
+		if k.Bit(i) == 0 {
+			r1 = arithmetic.addJacobian(r0, r1)
+			r0 = arithmetic.doubleJacobian(r0)
+		} else {
+			r0 = arithmetic.addJacobian(r0, r1)
+			r1 = arithmetic.doubleJacobian(r1)
+		}
+	}
+
+	return arithmetic.toAffine(r0)
+}
@@ -0,0 +1,85 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestScalarMultLadder(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var n = curve.Params().N
+		var key = generateKey(t, curve)
+
+		var scalars = []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), new(big.Int).Sub(n, big.NewInt(1)), new(big.Int).Add(n, big.NewInt(1))}
+		for i := 0; i < 50; i++ {
+			k, err := GeneratePreMessageSecret(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scalars = append(scalars, k)
+		}
+
+		for _, k := range scalars {
+			x, y := scalarMultLadder(curve, key.PublicX, key.PublicY, k)
+			wantX, wantY := curve.ScalarMult(key.PublicX, key.PublicY, k.Bytes())
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Fatalf("%s: scalarMultLadder(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, k, x, y, wantX, wantY)
+			}
+		}
+
+		for _, k := range []*big.Int{new(big.Int), n} {
+			if x, y := scalarMultLadder(curve, key.PublicX, key.PublicY, k); x.Sign() != 0 || y.Sign() != 0 {
+				t.Errorf("%s: scalarMultLadder(%x) = (%x, %x), want (0, 0)", curve.Params().Name, k, x, y)
+			}
+		}
+	}
+}
+
+func TestLadderCurve(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var ladder = LadderCurve(curve)
+		if ladder.Params() != curve.Params() {
+			t.Fatalf("%s: LadderCurve has other parameters", curve.Params().Name)
+		}
+
+		k, err := GeneratePreMessageSecret(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y := ladder.ScalarBaseMult(k.Bytes())
+		if wantX, wantY := curve.ScalarBaseMult(k.Bytes()); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("%s: ScalarBaseMult(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, k, x, y, wantX, wantY)
+		}
+
+		// Keys and signatures of either curve are accepted by the other
+		var key = generateKey(t, ladder)
+		if !curve.IsOnCurve(key.PublicX, key.PublicY) {
+			t.Fatalf("%s: public key of the ladder is not on the curve", curve.Params().Name)
+		}
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+			t.Errorf("%s: signature of the ladder does not verify on the curve", curve.Params().Name)
+		}
+
+		key = generateKey(t, curve)
+		if r, s, err = Sign(key, testMessageHash[:]); err != nil {
+			t.Fatal(err)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, ladder, testMessageHash[:]) {
+			t.Errorf("%s: signature of the curve does not verify on the ladder", curve.Params().Name)
+		}
+	}
+
+	// The ladder of a curve of NewCurve, e.g. the toy curve
+	var toy = toyCurve(t)
+	var toyLadder = LadderCurve(toy)
+	for k := int64(1); k < 200; k++ {
+		x, y := toyLadder.ScalarBaseMult(big.NewInt(k).Bytes())
+		if wantX, wantY := toy.ScalarBaseMult(big.NewInt(k).Bytes()); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("toy: ScalarBaseMult(%d) = (%v, %v), want (%v, %v)", k, x, y, wantX, wantY)
+		}
+	}
+}
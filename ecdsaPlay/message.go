@@ -0,0 +1,108 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// Hashes the message with hashFunc and signs the resulting hash as per SignV2.
+// hashFunc must be linked into the binary, i.e. hashFunc.Available(); the
+// SHA-3 hashes such as crypto.SHA3_256 become available by importing
+// golang.org/x/crypto/sha3, which registers them
+func SignMessage(key Key, message []byte, hashFunc crypto.Hash) (Signature, error) {
+	messageHash, err := hashMessage(message, hashFunc)
+	if err != nil {
+		return Signature{}, err
+	}
+	return SignV2(key, messageHash)
+}
+
+// Hashes the message with hashFunc and verifies the signature against the
+// resulting hash as per VerifyV2. An unavailable hashFunc fails verification
+func VerifyMessage(sig Signature, pub PublicKey, message []byte, hashFunc crypto.Hash) bool {
+	messageHash, err := hashMessage(message, hashFunc)
+	if err != nil {
+		return false
+	}
+	return VerifyV2(sig, pub, messageHash)
+}
+
+// Hashes the message with a hash.Hash returned by newHash and signs the
+// resulting hash as per SignV2. This covers hashes without a crypto.Hash
+// value, notably the Keccak-256 of Ethereum, which differs from SHA3-256 in
+// its padding:
+//
+//	SignMessageWith(key, message, sha3.NewLegacyKeccak256)
+//
+// with sha3 being golang.org/x/crypto/sha3
+func SignMessageWith(key Key, message []byte, newHash func() hash.Hash) (Signature, error) {
+	if newHash == nil {
+		return Signature{}, errors.New("Error: Missing hash function")
+	}
+	var h = newHash()
+	h.Write(message)
+	return SignV2(key, h.Sum(nil))
+}
+
+// Hashes the message with a hash.Hash returned by newHash and verifies the
+// signature against the resulting hash as per VerifyV2, see SignMessageWith
+func VerifyMessageWith(sig Signature, pub PublicKey, message []byte, newHash func() hash.Hash) bool {
+	if newHash == nil {
+		return false
+	}
+	var h = newHash()
+	h.Write(message)
+	return VerifyV2(sig, pub, h.Sum(nil))
+}
+
+// Signs z, a hash computed by the caller in a non-standard way such as
+// H(domain || messageHash) for domain separation, as the integer HashToInt(z).
+// So that no bits of the integer are left to chance, z must be at least as
+// long as N, e.g. 32 bytes for P-256 and 66 bytes for P-521
+func SignPrehashed(key Key, z []byte) (Signature, error) {
+	if err := checkPrivateKey(key); err != nil {
+		return Signature{}, err
+	}
+	if err := checkPrehashed(key.Curve, z); err != nil {
+		return Signature{}, err
+	}
+	return SignV2(key, z)
+}
+
+// Verifies the signature against z as per VerifyV2, with the same length
+// requirement as SignPrehashed
+func VerifyPrehashed(sig Signature, pub PublicKey, z []byte) bool {
+	if pub.Curve == nil || checkPrehashed(pub.Curve, z) != nil {
+		return false
+	}
+	return VerifyV2(sig, pub, z)
+}
+
+// Rejects a z shorter than the byte length of N
+func checkPrehashed(curve elliptic.Curve, z []byte) error {
+	if byteLen := (curve.Params().N.BitLen() + 7) / 8; len(z) < byteLen {
+		return fmt.Errorf("%w, prehash of %d bytes shorter than the %d bytes of N", ErrInvalidHashLength, len(z), byteLen)
+	}
+	return nil
+}
+
+// Hash of the message using hashFunc
+func hashMessage(message []byte, hashFunc crypto.Hash) ([]byte, error) {
+	h, err := newHash(hashFunc)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(message)
+	return h.Sum(nil), nil
+}
+
+// New hash.Hash of hashFunc, which must be available
+func newHash(hashFunc crypto.Hash) (hash.Hash, error) {
+	if !hashFunc.Available() {
+		return nil, errors.New("Error: Unavailable hash function " + hashFunc.String())
+	}
+	return hashFunc.New(), nil
+}
@@ -0,0 +1,235 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"strconv"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func TestSignMessage(t *testing.T) {
+	var message = []byte("Take the red pill!")
+	var sum256 = sha256.Sum256(message)
+	var sum512 = sha512.Sum512(message)
+
+	var tests = []struct {
+		hashFunc crypto.Hash
+		digest   []byte
+		other    crypto.Hash
+	}{
+		{crypto.SHA256, sum256[:], crypto.SHA512},
+		{crypto.SHA512, sum512[:], crypto.SHA256},
+	}
+
+	for _, curve := range nistCurves {
+		var key = generateKey(t, curve)
+		for _, test := range tests {
+			sig, err := SignMessage(key, message, test.hashFunc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyMessage(sig, key.PublicKey(), message, test.hashFunc) {
+				t.Errorf("%s %v: VerifyMessage rejected the signature", curve.Params().Name, test.hashFunc)
+			}
+			// The message is hashed internally, so the signature is that of
+			// its digest
+			if !VerifyV2(sig, key.PublicKey(), test.digest) {
+				t.Errorf("%s %v: VerifyV2 of the digest rejected the signature", curve.Params().Name, test.hashFunc)
+			}
+			if VerifyMessage(sig, key.PublicKey(), message, test.other) {
+				t.Errorf("%s %v: VerifyMessage accepted the signature under %v", curve.Params().Name, test.hashFunc, test.other)
+			}
+			if VerifyMessage(sig, key.PublicKey(), []byte("Take the blue pill!"), test.hashFunc) {
+				t.Errorf("%s %v: VerifyMessage accepted another message", curve.Params().Name, test.hashFunc)
+			}
+
+			var newHash = sha256.New
+			if test.hashFunc == crypto.SHA512 {
+				newHash = sha512.New
+			}
+			if !VerifyMessageWith(sig, key.PublicKey(), message, newHash) {
+				t.Errorf("%s %v: VerifyMessageWith rejected the signature", curve.Params().Name, test.hashFunc)
+			}
+		}
+	}
+}
+
+func TestSignMessageUnavailableHash(t *testing.T) {
+	// MD4 lives in golang.org/x/crypto and is not linked into the test
+	var key = generateKey(t, nistCurves[1])
+	if crypto.MD4.Available() {
+		t.Skip("MD4 is linked in")
+	}
+	if _, err := SignMessage(key, []byte("message"), crypto.MD4); err == nil {
+		t.Error("SignMessage with an unavailable hash succeeded")
+	}
+	sig, err := SignMessage(key, []byte("message"), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if VerifyMessage(sig, key.PublicKey(), []byte("message"), crypto.MD4) {
+		t.Error("VerifyMessage with an unavailable hash succeeded")
+	}
+	if _, err := SignMessageWith(key, []byte("message"), nil); err == nil {
+		t.Error("SignMessageWith without a hash function succeeded")
+	}
+	if VerifyMessageWith(sig, key.PublicKey(), []byte("message"), nil) {
+		t.Error("VerifyMessageWith without a hash function succeeded")
+	}
+}
+
+// z = H(domain || messageHash) for a domain tag of the caller
+func domainPrehash(newHash func() hash.Hash, domain string, messageHash []byte) []byte {
+	var h = newHash()
+	h.Write([]byte(domain))
+	h.Write(messageHash)
+	return h.Sum(nil)
+}
+
+func TestSignPrehashed(t *testing.T) {
+	const domain = "ecdsaPlay/test/v1"
+
+	var tests = []struct {
+		curve   elliptic.Curve
+		newHash func() hash.Hash
+		want    error
+	}{
+		{elliptic.P224(), sha256.New, nil},
+		{elliptic.P256(), sha256.New, nil},
+		{Secp256k1(), sha256.New, nil},
+		{elliptic.P384(), sha512.New, nil},
+		// 64 bytes of SHA-512 fall short of the 66 bytes of N
+		{elliptic.P521(), sha512.New, ErrInvalidHashLength},
+		{elliptic.P384(), sha256.New, ErrInvalidHashLength},
+	}
+
+	for _, test := range tests {
+		var name = test.curve.Params().Name
+		var key = generateKey(t, test.curve)
+		var z = domainPrehash(test.newHash, domain, testMessageHash[:])
+
+		sig, err := SignPrehashed(key, z)
+		if test.want != nil {
+			if !errors.Is(err, test.want) {
+				t.Errorf("%s: SignPrehashed of %d bytes: err = %v, want %v", name, len(z), err, test.want)
+			}
+			if VerifyPrehashed(sig, key.PublicKey(), z) {
+				t.Errorf("%s: VerifyPrehashed accepted %d bytes", name, len(z))
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: SignPrehashed: %v", name, err)
+		}
+
+		if !VerifyPrehashed(sig, key.PublicKey(), z) {
+			t.Errorf("%s: VerifyPrehashed rejected the signature", name)
+		}
+		// z is mapped as by HashToInt, so the signature is that of z itself
+		if !VerifyV2(sig, key.PublicKey(), z) {
+			t.Errorf("%s: VerifyV2 of z rejected the signature", name)
+		}
+		if other := domainPrehash(test.newHash, "ecdsaPlay/test/v2", testMessageHash[:]); VerifyPrehashed(sig, key.PublicKey(), other) {
+			t.Errorf("%s: VerifyPrehashed accepted another domain tag", name)
+		}
+		if VerifyPrehashed(sig, key.PublicKey(), testMessageHash[:]) {
+			t.Errorf("%s: VerifyPrehashed accepted the bare message hash", name)
+		}
+		if short := z[:(test.curve.Params().N.BitLen()+7)/8-1]; VerifyPrehashed(sig, key.PublicKey(), short) {
+			t.Errorf("%s: VerifyPrehashed accepted z of %d bytes", name, len(short))
+		}
+		if VerifyPrehashed(sig, PublicKey{}, z) {
+			t.Errorf("%s: VerifyPrehashed accepted a public key without a curve", name)
+		}
+	}
+}
+
+// Hash of an eth_sign (EIP-191 version 0x45) message, over which Ethereum
+// wallets sign
+func ethereumMessageHash(message []byte) []byte {
+	var h = sha3.NewLegacyKeccak256()
+	h.Write([]byte("\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))))
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+func TestSignKeccak256Ethereum(t *testing.T) {
+	// Keccak-256 rather than SHA3-256, which pads differently
+	var empty = sha3.NewLegacyKeccak256().Sum(nil)
+	if want := hexBytes("c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"); !bytes.Equal(empty, want) {
+		t.Fatalf("Keccak-256 of \"\" = %x, want %x", empty, want)
+	}
+
+	// Account and signature of "Some data" from the web3.js documentation
+	var curve = Secp256k1()
+	key, err := NewKeyFromScalar(curve, hexInt("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var address = sha3.NewLegacyKeccak256()
+	address.Write(SerializePublicKey(key.PublicKey(), false)[1:])
+	if got, want := address.Sum(nil)[12:], hexBytes("2c7536e3605d9c16a7a3d7b1898e529396a65c23"); !bytes.Equal(got, want) {
+		t.Fatalf("address = %x, want %x", got, want)
+	}
+
+	var message = []byte("Some data")
+	var messageHash = ethereumMessageHash(message)
+	if want := hexBytes("1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"); !bytes.Equal(messageHash, want) {
+		t.Fatalf("message hash = %x, want %x", messageHash, want)
+	}
+
+	sig, recid, err := DecodeEthereumSignature(hexBytes("b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd" +
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyV2(sig, key.PublicKey(), messageHash) {
+		t.Error("VerifyV2 rejected the web3.js signature")
+	}
+	x, y, err := RecoverPublicKey(sig, recid, curve, messageHash)
+	if err != nil || x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+		t.Errorf("RecoverPublicKey = (%x, %x), %v, want (%x, %x)", x, y, err, key.PublicX, key.PublicY)
+	}
+
+	// web3.js signs with RFC 6979 and HMAC-SHA256, as SignDeterministic does
+	// for a 32 byte hash
+	r, s, err := SignDeterministic(key, messageHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(sig.R) != 0 || s.Cmp(sig.S) != 0 {
+		t.Errorf("SignDeterministic = (%x, %x), want (%x, %x)", r, s, sig.R, sig.S)
+	}
+
+	// Signing the prefixed message with Keccak-256 as the hash
+	var prefixed = append([]byte("\x19Ethereum Signed Message:\n9"), message...)
+	signed, err := SignMessageWith(key, prefixed, sha3.NewLegacyKeccak256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyMessageWith(signed, key.PublicKey(), prefixed, sha3.NewLegacyKeccak256) || !VerifyV2(signed, key.PublicKey(), messageHash) {
+		t.Error("VerifyMessageWith rejected the Keccak-256 signature")
+	}
+	if VerifyMessageWith(signed, key.PublicKey(), prefixed, sha3.New256) {
+		t.Error("VerifyMessageWith accepted the Keccak-256 signature under SHA3-256")
+	}
+
+	// golang.org/x/crypto/sha3 registers SHA3-256 as a crypto.Hash
+	if !crypto.SHA3_256.Available() {
+		t.Fatal("SHA3-256 unavailable with golang.org/x/crypto/sha3 imported")
+	}
+	sha3Sig, err := SignMessage(key, message, crypto.SHA3_256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyMessage(sha3Sig, key.PublicKey(), message, crypto.SHA3_256) || !VerifyMessageWith(sha3Sig, key.PublicKey(), message, sha3.New256) {
+		t.Error("VerifyMessage rejected the SHA3-256 signature")
+	}
+}
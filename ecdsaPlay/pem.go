@@ -0,0 +1,111 @@
+package ecdsaplay
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Encodes the key pair as a PEM block of type "PRIVATE KEY" holding the
+// PKCS #8 encoding of the private key and its curve
+func MarshalPKCS8PEM(key Key) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.ecdsaPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Decodes a key pair from a PEM block of type "PRIVATE KEY" (PKCS #8) or
+// "EC PRIVATE KEY" (SEC 1, as produced by `openssl ecparam -genkey`). Leading
+// blocks of any other type, such as "EC PARAMETERS", are skipped
+func ParsePKCS8PEM(pemBytes []byte) (Key, error) {
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			return Key{}, errors.New("Error: Invalid PEM, no private key found")
+		}
+
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return Key{}, err
+			}
+			privateKey, ok := parsed.(*ecdsa.PrivateKey)
+			if !ok {
+				return Key{}, errors.New("Error: Invalid PEM, private key is not an ECDSA key")
+			}
+			return keyFromECDSA(privateKey), nil
+
+		case "EC PRIVATE KEY":
+			privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return Key{}, err
+			}
+			return keyFromECDSA(privateKey), nil
+		}
+	}
+}
+
+// Standard library *ecdsa.PrivateKey shim holding the same values as key
+func (k Key) ecdsaPrivateKey() *ecdsa.PrivateKey {
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: k.Curve, X: k.PublicX, Y: k.PublicY},
+		D:         k.Private,
+	}
+}
+
+// Key holding the same values as the standard library *ecdsa.PrivateKey
+func keyFromECDSA(privateKey *ecdsa.PrivateKey) Key {
+	return Key{
+		Private: privateKey.D,
+		PublicX: privateKey.X,
+		PublicY: privateKey.Y,
+		Curve:   privateKey.Curve,
+	}
+}
+
+// Encodes the public key as a PEM block of type "PUBLIC KEY" holding its
+// SubjectPublicKeyInfo, as read by ParsePublicKeyPEM and `openssl ec -pubin`
+func MarshalPublicKeyPEM(pub PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(&ecdsa.PublicKey{Curve: pub.Curve, X: pub.X, Y: pub.Y})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Decodes a public key from a PEM block of type "PUBLIC KEY" holding a
+// SubjectPublicKeyInfo, as produced by `openssl ec -pubout`
+func ParsePublicKeyPEM(pemBytes []byte) (PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			return PublicKey{}, errors.New("Error: Invalid PEM, no public key found")
+		}
+		if block.Type == "PUBLIC KEY" {
+			return ParsePublicKeyDER(block.Bytes)
+		}
+	}
+}
+
+// Decodes a public key from the DER encoding of a SubjectPublicKeyInfo.
+// Keys other than ECDSA, such as RSA or Ed25519, are rejected
+func ParsePublicKeyDER(der []byte) (PublicKey, error) {
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return PublicKey{}, err
+	}
+
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return PublicKey{}, fmt.Errorf("Error: Unsupported public key type %T, not an ECDSA key", parsed)
+	}
+
+	return PublicKey{X: publicKey.X, Y: publicKey.Y, Curve: publicKey.Curve}, nil
+}
@@ -0,0 +1,214 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Encodes the public key as the compressed SEC 1 form, i.e. 0x02 or 0x03
+// (for even or odd PublicY) followed by PublicX as a fixed-size big-endian
+// value, e.g. 33 bytes for P-256
+func MarshalCompressed(key Key) []byte {
+	return SerializePublicKey(key.PublicKey(), true)
+}
+
+// Encodes the public key in the SEC 1 form: compressed as per
+// MarshalCompressed, or uncompressed as 0x04 followed by X and Y, each as a
+// fixed-size big-endian value, e.g. 65 bytes for P-256. A public key with a
+// missing curve or coordinate yields nil
+func SerializePublicKey(pub PublicKey, compressed bool) []byte {
+	if pub.Curve == nil || pub.X == nil || pub.Y == nil {
+		return nil
+	}
+
+	var byteLen = (pub.Curve.Params().BitSize + 7) / 8
+	if compressed {
+		var b = make([]byte, 1+byteLen)
+		b[0] = byte(2 + pub.Y.Bit(0))
+		pub.X.FillBytes(b[1:])
+		return b
+	}
+
+	var b = make([]byte, 1+2*byteLen)
+	b[0] = 0x04
+	pub.X.FillBytes(b[1 : 1+byteLen])
+	pub.Y.FillBytes(b[1+byteLen:])
+	return b
+}
+
+// Decodes a public key encoded by SerializePublicKey, telling the compressed
+// form (0x02 or 0x03) from the uncompressed one (0x04) by the prefix byte.
+// The point must lie on the curve and must not be the point at infinity
+func DeserializePublicKey(curve elliptic.Curve, data []byte) (PublicKey, error) {
+	if len(data) == 0 {
+		return PublicKey{}, errors.New("Error: Invalid public key encoding, empty")
+	}
+
+	var x, y *big.Int
+	switch data[0] {
+	case 0x02, 0x03:
+		var err error
+		if x, y, err = UnmarshalCompressed(curve, data); err != nil {
+			return PublicKey{}, err
+		}
+	case 0x04:
+		var byteLen = (curve.Params().BitSize + 7) / 8
+		if len(data) != 1+2*byteLen {
+			return PublicKey{}, errors.New("Error: Invalid uncompressed point encoding")
+		}
+		x = new(big.Int).SetBytes(data[1 : 1+byteLen])
+		y = new(big.Int).SetBytes(data[1+byteLen:])
+	default:
+		return PublicKey{}, fmt.Errorf("Error: Invalid public key encoding, unknown prefix 0x%02x", data[0])
+	}
+
+	if err := validatePublicPoint(curve, x, y); err != nil {
+		return PublicKey{}, err
+	}
+	return PublicKey{X: x, Y: y, Curve: curve}, nil
+}
+
+// Decodes the compressed SEC 1 form of a point by solving the curve equation
+// y^2 = x^3 + ax + b for y and choosing the root whose parity is given by the
+// prefix byte
+func UnmarshalCompressed(curve elliptic.Curve, data []byte) (x, y *big.Int, err error) {
+	var byteLen = (curve.Params().BitSize + 7) / 8
+	if len(data) != 1+byteLen || (data[0] != 2 && data[0] != 3) {
+		return nil, nil, errors.New("Error: Invalid compressed point encoding")
+	}
+
+	var p = curve.Params().P
+	x = new(big.Int).SetBytes(data[1:])
+	if x.Cmp(p) >= 0 {
+		return nil, nil, errors.New("Error: Invalid compressed point, x outside of the field")
+	}
+
+	y = curveEquation(curve, x)
+	if y.ModSqrt(y, p) == nil {
+		return nil, nil, fmt.Errorf("%w, invalid compressed point", ErrPointNotOnCurve)
+	}
+
+	// Choosing the root with the requested parity
+	if y.Bit(0) != uint(data[0]&1) {
+		y.Sub(p, y)
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, nil, fmt.Errorf("%w, invalid compressed point", ErrPointNotOnCurve)
+	}
+
+	return x, y, nil
+}
+
+// Verification as per Verify against a public key in the compressed SEC 1
+// form, e.g. 33 bytes for P-256 and secp256k1. An encoding UnmarshalCompressed
+// rejects is returned as an error, whereas a signature that does not verify
+// against a well formed key is reported as false with no error
+func VerifyCompressed(r, s *big.Int, compressedPub []byte, curve elliptic.Curve, messageHash []byte) (bool, error) {
+	x, y, err := UnmarshalCompressed(curve, compressedPub)
+	if err != nil {
+		return false, err
+	}
+	return Verify(r, s, x, y, curve, messageHash), nil
+}
+
+// Verification as per Verify against the public key given by its
+// x-coordinate and the parity of its y-coordinate (0 for even, 1 for odd),
+// as stored by compact formats. y is recovered from the curve equation as
+// per UnmarshalCompressed, so an x for which x^3 + ax + b has no square root
+// is returned as an error rather than reported as false
+func VerifyXOnly(r, s, publicKeyX *big.Int, yParity int, curve elliptic.Curve, messageHash []byte) (bool, error) {
+	if yParity != 0 && yParity != 1 {
+		return false, errors.New("Error: Invalid y parity, must be 0 or 1")
+	}
+	var byteLen = (curve.Params().BitSize + 7) / 8
+	if publicKeyX == nil || publicKeyX.Sign() < 0 || publicKeyX.BitLen() > byteLen*8 {
+		return false, errors.New("Error: Invalid public key, x outside of the field")
+	}
+
+	var compressed = make([]byte, 1+byteLen)
+	compressed[0] = byte(2 + yParity)
+	publicKeyX.FillBytes(compressed[1:])
+	return VerifyCompressed(r, s, compressed, curve, messageHash)
+}
+
+// Calculates x^3 + ax + b mod p, the right hand side of the curve equation;
+// where, a = -3 for the NIST curves implemented by crypto/elliptic
+func curveEquation(curve elliptic.Curve, x *big.Int) *big.Int {
+	var p = curve.Params().P
+
+	var x3 = new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+
+	var ax = new(big.Int).Mul(curveA(curve), x)
+
+	x3.Add(x3, ax)
+	x3.Add(x3, curve.Params().B)
+	x3.Mod(x3, p)
+
+	return x3
+}
+
+// Cofactor h of the curve, i.e. the number of points divided by N
+func curveCofactor(curve elliptic.Curve) *big.Int {
+	if c, ok := curve.(*weierstrassCurve); ok && c.cofactor != nil {
+		return c.cofactor
+	}
+	return big.NewInt(1)
+}
+
+// Coefficient a of the curve equation y^2 = x^3 + ax + b
+func curveA(curve elliptic.Curve) *big.Int {
+	if c, ok := curve.(*weierstrassCurve); ok {
+		return c.a
+	}
+	return big.NewInt(-3)
+}
+
+// Validates a public key point by rejecting the point at infinity, points
+// that do not satisfy the curve equation, points of low order, i.e. those
+// where h*P is the point at infinity for the cofactor h, and points outside
+// of the prime order subgroup generated by G, i.e. those where N*P is not the
+// point at infinity
+func ValidatePublicKey(curve elliptic.Curve, x, y *big.Int) error {
+	if err := validatePublicPoint(curve, x, y); err != nil {
+		return err
+	}
+
+	// h*P = point at infinity for points in the small subgroups of a curve of
+	// cofactor h > 1. The NIST curves and secp256k1 have h = 1, for which
+	// h*P = P and the check is skipped
+	if h := curveCofactor(curve); h.Cmp(big.NewInt(1)) > 0 {
+		hPx, hPy := curve.ScalarMult(x, y, h.Bytes())
+		if hPx.Sign() == 0 && hPy.Sign() == 0 {
+			return errors.New("Error: Invalid public key, point of low order")
+		}
+	}
+
+	// N*P = point at infinity, represented as (0, 0) by crypto/elliptic
+	nPx, nPy := curve.ScalarMult(x, y, curve.Params().N.Bytes())
+	if nPx.Sign() != 0 || nPy.Sign() != 0 {
+		return errors.New("Error: Invalid public key, not in the subgroup of order N")
+	}
+
+	return nil
+}
+
+// Rejects the point at infinity and points not on the curve. For the prime
+// order NIST curves this is sufficient for the point to be in the subgroup
+// generated by G, which lets Verify skip the extra scalar multiplication
+// performed by ValidatePublicKey
+func validatePublicPoint(curve elliptic.Curve, x, y *big.Int) error {
+	if x == nil || y == nil {
+		return errors.New("Error: Invalid public key, missing coordinate")
+	}
+	if isInfinity(x, y) {
+		return ErrIdentityPublicKey
+	}
+	if !curve.IsOnCurve(x, y) {
+		return fmt.Errorf("%w, invalid public key", ErrPointNotOnCurve)
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestMarshalCompressedMatchesElliptic(t *testing.T) {
+	for _, curve := range nistCurves {
+		for i := 0; i < 20; i++ {
+			var key = generateKey(t, curve)
+
+			var compressed = MarshalCompressed(key)
+			if want := elliptic.MarshalCompressed(curve, key.PublicX, key.PublicY); !bytes.Equal(compressed, want) {
+				t.Fatalf("%s: MarshalCompressed = %x, want %x", curve.Params().Name, compressed, want)
+			}
+
+			x, y, err := UnmarshalCompressed(curve, compressed)
+			if err != nil {
+				t.Fatalf("%s: UnmarshalCompressed(%x): %v", curve.Params().Name, compressed, err)
+			}
+			wantX, wantY := elliptic.UnmarshalCompressed(curve, compressed)
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 || x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+				t.Fatalf("%s: UnmarshalCompressed(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, compressed, x, y, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestUnmarshalCompressedInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var valid = MarshalCompressed(generateKey(t, curve))
+
+	// An x for which x^3 - 3x + b is not a square mod P, as elliptic rejects
+	var notOnCurve = make([]byte, len(valid))
+	notOnCurve[0] = 0x02
+	for x := int64(1); ; x++ {
+		big.NewInt(x).FillBytes(notOnCurve[1:])
+		if px, _ := elliptic.UnmarshalCompressed(curve, notOnCurve); px == nil {
+			break
+		}
+	}
+
+	var outsideField = append([]byte{0x03}, curve.Params().P.Bytes()...)
+
+	var tests = []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated", valid[:len(valid)-1]},
+		{"too long", append(append([]byte{}, valid...), 0x00)},
+		{"uncompressed prefix", append([]byte{0x04}, valid[1:]...)},
+		{"x not on the curve", notOnCurve},
+		{"x = P", outsideField},
+	}
+
+	for _, test := range tests {
+		if x, y, err := UnmarshalCompressed(curve, test.data); err == nil {
+			t.Errorf("%s: UnmarshalCompressed(%x) = (%x, %x), want an error", test.name, test.data, x, y)
+		}
+	}
+}
+
+func TestValidatePublicKey(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		if err := ValidatePublicKey(curve, key.PublicX, key.PublicY); err != nil {
+			t.Fatalf("%s: ValidatePublicKey rejected a generated key: %v", curve.Params().Name, err)
+		}
+
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var tests = []struct {
+			name string
+			x, y *big.Int
+			want error
+		}{
+			{"y + 1", key.PublicX, new(big.Int).Add(key.PublicY, big.NewInt(1)), ErrPointNotOnCurve},
+			{"x + 1", new(big.Int).Add(key.PublicX, big.NewInt(1)), key.PublicY, ErrPointNotOnCurve},
+			{"y + P", key.PublicX, new(big.Int).Add(key.PublicY, curve.Params().P), ErrPointNotOnCurve},
+			{"infinity", new(big.Int), new(big.Int), ErrIdentityPublicKey},
+			{"missing y", key.PublicX, nil, nil},
+		}
+
+		for _, test := range tests {
+			err := ValidatePublicKey(curve, test.x, test.y)
+			if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+				t.Errorf("%s %s: ValidatePublicKey = %v, want %v", curve.Params().Name, test.name, err, test.want)
+			}
+			if Verify(r, s, test.x, test.y, curve, testMessageHash[:]) {
+				t.Errorf("%s %s: Verify accepted the signature under an invalid public key", curve.Params().Name, test.name)
+			}
+		}
+	}
+}
+
+// y^2 = x^3 - 3x + 18 over the field of order 1019 has 1006 points, i.e. a
+// subgroup of prime order 503 generated by G = (1017, 1015) and the cofactor
+// 2, with (1016, 0) of order 2 and (3, 1013) of order 1006
+func cofactorCurve(t *testing.T) elliptic.Curve {
+	t.Helper()
+	curve, err := NewCurve("cofactor 2", big.NewInt(1019), big.NewInt(-3), big.NewInt(18), big.NewInt(1017), big.NewInt(1015), big.NewInt(503), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return curve
+}
+
+func TestValidatePublicKeyLowOrder(t *testing.T) {
+	var curve = cofactorCurve(t)
+	if h := curveCofactor(curve); h.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("cofactor = %v, want 2", h)
+	}
+
+	var gx, gy = curve.ScalarBaseMult([]byte{42})
+	var tests = []struct {
+		name string
+		x, y *big.Int
+		ok   bool
+	}{
+		{"42G", gx, gy, true},
+		{"G", curve.Params().Gx, curve.Params().Gy, true},
+		{"order 2", big.NewInt(1016), big.NewInt(0), false},
+		{"order 1006", big.NewInt(3), big.NewInt(1013), false},
+	}
+
+	for _, test := range tests {
+		if !curve.IsOnCurve(test.x, test.y) {
+			t.Fatalf("%s: (%v, %v) is not on the curve", test.name, test.x, test.y)
+		}
+		if err := ValidatePublicKey(curve, test.x, test.y); (err == nil) != test.ok {
+			t.Errorf("%s: ValidatePublicKey(%v, %v) = %v, want ok %v", test.name, test.x, test.y, err, test.ok)
+		}
+	}
+
+	// For the cofactor 1 of the NIST curves and secp256k1 the check is a no-op
+	for _, c := range append(nistCurves, Secp256k1()) {
+		if h := curveCofactor(c); h.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("%s: cofactor = %v, want 1", c.Params().Name, h)
+		}
+	}
+}
+
+func TestVerifyCompressed(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var compressed = MarshalCompressed(key)
+		var otherHash = sha256.Sum256([]byte("Take the blue pill!"))
+
+		// A well formed key gives no error, whether the signature verifies
+		var flipped = append([]byte{}, compressed...)
+		flipped[0] ^= 0x01
+		var tests = []struct {
+			name        string
+			pub         []byte
+			r           *big.Int
+			messageHash []byte
+			want        bool
+		}{
+			{"valid", compressed, r, testMessageHash[:], true},
+			{"other hash", compressed, r, otherHash[:], false},
+			{"r + 1", compressed, new(big.Int).Add(r, big.NewInt(1)), testMessageHash[:], false},
+			{"negated key", flipped, r, testMessageHash[:], false},
+		}
+		for _, test := range tests {
+			valid, err := VerifyCompressed(test.r, s, test.pub, curve, test.messageHash)
+			if err != nil || valid != test.want {
+				t.Errorf("%s %s: VerifyCompressed = %v, %v, want %v, nil", curve.Params().Name, test.name, valid, err, test.want)
+			}
+		}
+
+		// A malformed encoding is an error rather than a mere false
+		var invalid = []struct {
+			name string
+			pub  []byte
+		}{
+			{"empty", nil},
+			{"truncated", compressed[:len(compressed)-1]},
+			{"uncompressed prefix", append([]byte{0x04}, compressed[1:]...)},
+			{"uncompressed", elliptic.Marshal(curve, key.PublicX, key.PublicY)},
+			{"x = P", append([]byte{0x02}, curve.Params().P.Bytes()...)},
+		}
+		for _, test := range invalid {
+			if valid, err := VerifyCompressed(r, s, test.pub, curve, testMessageHash[:]); valid || err == nil {
+				t.Errorf("%s %s: VerifyCompressed = %v, %v, want false and an error", curve.Params().Name, test.name, valid, err)
+			}
+		}
+	}
+}
+
+func TestSerializePublicKeyRoundTrip(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var byteLen = (curve.Params().BitSize + 7) / 8
+		for i := 0; i < 10; i++ {
+			var pub = generateKey(t, curve).PublicKey()
+
+			var tests = []struct {
+				compressed bool
+				length     int
+				want       []byte
+			}{
+				{true, 1 + byteLen, elliptic.MarshalCompressed(curve, pub.X, pub.Y)},
+				{false, 1 + 2*byteLen, elliptic.Marshal(curve, pub.X, pub.Y)},
+			}
+
+			for _, test := range tests {
+				var data = SerializePublicKey(pub, test.compressed)
+				if len(data) != test.length || !bytes.Equal(data, test.want) {
+					t.Fatalf("%s: SerializePublicKey(compressed %v) = %x, want %x", curve.Params().Name, test.compressed, data, test.want)
+				}
+				decoded, err := DeserializePublicKey(curve, data)
+				if err != nil {
+					t.Fatalf("%s: DeserializePublicKey(%x): %v", curve.Params().Name, data, err)
+				}
+				if decoded.X.Cmp(pub.X) != 0 || decoded.Y.Cmp(pub.Y) != 0 || decoded.Curve != curve {
+					t.Errorf("%s: DeserializePublicKey(%x) = (%x, %x), want (%x, %x)", curve.Params().Name, data, decoded.X, decoded.Y, pub.X, pub.Y)
+				}
+			}
+		}
+	}
+
+	if data := SerializePublicKey(PublicKey{}, true); data != nil {
+		t.Errorf("SerializePublicKey of an empty key = %x, want nil", data)
+	}
+}
+
+func TestDeserializePublicKeyInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var pub = generateKey(t, curve).PublicKey()
+	var uncompressed = SerializePublicKey(pub, false)
+	var compressed = SerializePublicKey(pub, true)
+
+	var offCurve = append([]byte{}, uncompressed...)
+	offCurve[len(offCurve)-1] ^= 0x01
+
+	var tests = []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, nil},
+		{"0x05 prefix", append([]byte{0x05}, uncompressed[1:]...), nil},
+		{"0x05 prefix, compressed length", append([]byte{0x05}, compressed[1:]...), nil},
+		{"0x00 prefix", []byte{0x00}, nil},
+		{"0x06 hybrid prefix", append([]byte{0x06}, uncompressed[1:]...), nil},
+		{"uncompressed truncated", uncompressed[:len(uncompressed)-1], nil},
+		{"compressed with the length of uncompressed", append([]byte{0x02}, uncompressed[1:]...), nil},
+		{"uncompressed with the length of compressed", append([]byte{0x04}, compressed[1:]...), nil},
+		{"off the curve", offCurve, ErrPointNotOnCurve},
+		{"infinity", make([]byte, len(uncompressed)), nil},
+		{"uncompressed infinity", append([]byte{0x04}, make([]byte, len(uncompressed)-1)...), ErrIdentityPublicKey},
+	}
+
+	for _, test := range tests {
+		decoded, err := DeserializePublicKey(curve, test.data)
+		if err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: DeserializePublicKey(%x) = (%v, %v), %v, want an error %v", test.name, test.data, decoded.X, decoded.Y, err, test.want)
+		}
+	}
+}
+
+func TestVerifyXOnly(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		for i := 0; i < 5; i++ {
+			var key = generateKey(t, curve)
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// x and the parity as carried by the compressed form
+			var compressed = MarshalCompressed(key)
+			var x = new(big.Int).SetBytes(compressed[1:])
+			var parity = int(compressed[0] & 1)
+			if parity != int(key.PublicY.Bit(0)) {
+				t.Fatalf("%s: prefix %x does not give the parity of y", curve.Params().Name, compressed[0])
+			}
+
+			if valid, err := VerifyXOnly(r, s, x, parity, curve, testMessageHash[:]); !valid || err != nil {
+				t.Errorf("%s: VerifyXOnly = %v, %v, want true", curve.Params().Name, valid, err)
+			}
+			// The other parity is the negated key, well formed but wrong
+			if valid, err := VerifyXOnly(r, s, x, 1-parity, curve, testMessageHash[:]); valid || err != nil {
+				t.Errorf("%s: VerifyXOnly of the other parity = %v, %v, want false, nil", curve.Params().Name, valid, err)
+			}
+			if valid, err := VerifyXOnly(r, new(big.Int).Add(s, big.NewInt(1)), x, parity, curve, testMessageHash[:]); valid || err != nil {
+				t.Errorf("%s: VerifyXOnly of s + 1 = %v, %v, want false, nil", curve.Params().Name, valid, err)
+			}
+		}
+	}
+}
+
+func TestVerifyXOnlyInvalid(t *testing.T) {
+	var curve = elliptic.P256()
+	var key = generateKey(t, curve)
+	r, s, err := Sign(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An x for which x^3 - 3x + b has no square root mod P
+	var notOnCurve *big.Int
+	var encoded = make([]byte, 33)
+	encoded[0] = 0x02
+	for x := int64(1); ; x++ {
+		big.NewInt(x).FillBytes(encoded[1:])
+		if px, _ := elliptic.UnmarshalCompressed(curve, encoded); px == nil {
+			notOnCurve = big.NewInt(x)
+			break
+		}
+	}
+
+	var tests = []struct {
+		name   string
+		x      *big.Int
+		parity int
+		want   error
+	}{
+		{"no square root", notOnCurve, 0, ErrPointNotOnCurve},
+		{"no square root, odd", notOnCurve, 1, ErrPointNotOnCurve},
+		{"parity 2", key.PublicX, 2, nil},
+		{"parity -1", key.PublicX, -1, nil},
+		{"nil x", nil, 0, nil},
+		{"negative x", new(big.Int).Neg(key.PublicX), 0, nil},
+		{"x = P", new(big.Int).Set(curve.Params().P), 0, nil},
+		{"x of 33 bytes", new(big.Int).Lsh(big.NewInt(1), 256), 0, nil},
+	}
+
+	for _, test := range tests {
+		valid, err := VerifyXOnly(r, s, test.x, test.parity, curve, testMessageHash[:])
+		if valid || err == nil || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("%s: VerifyXOnly = %v, %v, want false and an error %v", test.name, valid, err, test.want)
+		}
+	}
+}
+
+func TestUnmarshalCompressedSecp256k1(t *testing.T) {
+	var curve = Secp256k1()
+	for i := 0; i < 20; i++ {
+		var key = generateKey(t, curve)
+		x, y, err := UnmarshalCompressed(curve, MarshalCompressed(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+			t.Fatalf("UnmarshalCompressed gave (%x, %x), want (%x, %x)", x, y, key.PublicX, key.PublicY)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"sync"
+)
+
+// Scratch big.Ints for the intermediate values of signing and verification,
+// e.g. re, k^-1, s^-1, u and v, so that repeated calls reuse the words
+// backing them rather than allocating fresh ones each time. Values returned
+// to the caller are never taken from the pool
+var scratchPool = sync.Pool{
+	New: func() interface{} {
+		return new(big.Int)
+	},
+}
+
+// Takes a scratch big.Int from the pool, to be handed back with putScratch
+// once the calculation no longer needs it. Its value is 0
+func getScratch() *big.Int {
+	return scratchPool.Get().(*big.Int)
+}
+
+// Wipes x and returns it to the pool. Every word of the backing array is
+// overwritten, not only those of the current value, since the scratch values
+// derive from private keys and per-message secrets and the next user of x
+// may belong to another call entirely
+func putScratch(x *big.Int) {
+	var words = x.Bits()
+	words = words[:cap(words)]
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+	scratchPool.Put(x)
+}
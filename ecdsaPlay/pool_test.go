@@ -0,0 +1,65 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+)
+
+// Whether every word backing x, up to its capacity, is zero
+func wiped(x *big.Int) bool {
+	var words = x.Bits()
+	for _, w := range words[:cap(words)] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPutScratchWipes(t *testing.T) {
+	var key = generateKey(t, elliptic.P521())
+
+	// A secret value, then a shorter one, so that words beyond the current
+	// length still hold the secret
+	var x = new(big.Int).Set(key.Private)
+	x.Mul(x, x)
+	x.SetInt64(1)
+	if wiped(x) {
+		t.Fatal("backing array holds no trace of the secret")
+	}
+
+	putScratch(x)
+	if x.Sign() != 0 || !wiped(x) {
+		t.Errorf("putScratch left %x with backing words %x", x, x.Bits()[:cap(x.Bits())])
+	}
+}
+
+func TestScratchAfterSignVerify(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		for i := 0; i < 10; i++ {
+			r, s, err := Sign(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Fatalf("%s: signature does not verify", curve.Params().Name)
+			}
+		}
+
+		// Whatever the pool hands out after signing and verifying is 0, with
+		// none of the private key, k or their inverses left in its words
+		var taken []*big.Int
+		for i := 0; i < 16; i++ {
+			var x = getScratch()
+			if x.Sign() != 0 || !wiped(x) {
+				t.Errorf("%s: getScratch = %x with backing words %x", curve.Params().Name, x, x.Bits()[:cap(x.Bits())])
+			}
+			taken = append(taken, x)
+		}
+		for _, x := range taken {
+			putScratch(x)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// Window width of the w-NAF tables of KeyVerifier, wider than wnafWidth as
+// the tables are calculated once and reused by every verification
+const precomputedWidth = 6
+
+// Verifies signatures against a single public key, calculating the tables of
+// odd multiples of G and of the public key P once so that each verification
+// only needs the interleaved w-NAF evaluation of uG + vP, a single run of
+// doublings with no table setup. As with VerifyFast, the gain applies to the
+// curves implemented by this package such as Secp256k1; for the NIST curves
+// of crypto/elliptic, whose optimized field arithmetic remains faster, the
+// verification is that of Verify. A KeyVerifier is safe for concurrent use
+// by multiple goroutines
+type KeyVerifier struct {
+	pub        PublicKey
+	arithmetic *weierstrassCurve
+	gTable     []jacobianPoint
+	pTable     []jacobianPoint
+}
+
+// Returns a KeyVerifier for the public key, which is validated once here
+func VerifierForKey(pub PublicKey) (*KeyVerifier, error) {
+	if pub.Curve == nil {
+		return nil, errors.New("Error: Invalid public key, missing curve")
+	}
+	if err := validatePublicPoint(pub.Curve, pub.X, pub.Y); err != nil {
+		return nil, err
+	}
+
+	var verifier = &KeyVerifier{pub: PublicKey{X: new(big.Int).Set(pub.X), Y: new(big.Int).Set(pub.Y), Curve: pub.Curve}}
+
+	if arithmetic, ok := pub.Curve.(*weierstrassCurve); ok {
+		var params = arithmetic.params
+		verifier.arithmetic = arithmetic
+		verifier.gTable = arithmetic.affineOddMultiples(params.Gx, params.Gy, 1<<(precomputedWidth-2))
+		verifier.pTable = arithmetic.affineOddMultiples(pub.X, pub.Y, 1<<(precomputedWidth-2))
+	}
+
+	return verifier, nil
+}
+
+// Verification of the signature over the message hash as per VerifyV2
+func (kv *KeyVerifier) Verify(sig Signature, messageHash []byte) bool {
+	var combine linearCombination = scalarMultCombination
+	if kv.arithmetic != nil {
+		combine = kv.combine
+	}
+
+	valid, _ := verify(sig.R, sig.S, nil, kv.pub.X, kv.pub.Y, kv.pub.Curve, messageHash, combine)
+	return valid
+}
+
+// Calculates uG + vP from the w-NAF digits of u and v, adding the precomputed
+// odd multiples of G and P (or their negations) after a shared doubling
+func (kv *KeyVerifier) combine(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
+	var arithmetic = kv.arithmetic
+	var uDigits = wnaf(u, precomputedWidth)
+	var vDigits = wnaf(v, precomputedWidth)
+
+	var length = len(uDigits)
+	if len(vDigits) > length {
+		length = len(vDigits)
+	}
+
+	var result = jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	for i := length - 1; i >= 0; i-- {
+		result = arithmetic.doubleJacobian(result)
+
+		if i < len(uDigits) {
+			result = arithmetic.addWNAFDigit(result, kv.gTable, uDigits[i])
+		}
+		if i < len(vDigits) {
+			result = arithmetic.addWNAFDigit(result, kv.pTable, vDigits[i])
+		}
+	}
+
+	return arithmetic.toAffine(result)
+}
+
+// Adds d times the point of the odd multiples table to point, for a w-NAF
+// digit d, i.e. table[d/2] for d > 0 and its negation for d < 0
+func (curve *weierstrassCurve) addWNAFDigit(point jacobianPoint, table []jacobianPoint, d int) jacobianPoint {
+	switch {
+	case d > 0:
+		return curve.addJacobian(point, table[d/2])
+	case d < 0:
+		return curve.addJacobian(point, curve.negateJacobian(table[-d/2]))
+	}
+	return point
+}
+
+// Odd multiples of (x, y) as per oddMultiples, each converted back to Z = 1
+// so that additions of the table entries are cheaper
+func (curve *weierstrassCurve) affineOddMultiples(x, y *big.Int, count int) []jacobianPoint {
+	var table = curve.oddMultiples(curve.toJacobian(x, y), count)
+	for i := range table {
+		table[i] = curve.toJacobian(curve.toAffine(table[i]))
+	}
+	return table
+}
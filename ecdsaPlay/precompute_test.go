@@ -0,0 +1,103 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestKeyVerifierMatchesVerify(t *testing.T) {
+	// The NIST curves use Verify itself, while secp256k1 and the curves of
+	// NewCurve use the precomputed tables
+	for _, curve := range []elliptic.Curve{elliptic.P256(), Secp256k1(), genericCurve(t, elliptic.P256()), LadderCurve(elliptic.P384())} {
+		var key = generateKey(t, curve)
+		var pub = key.PublicKey()
+		verifier, err := VerifierForKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 50; i++ {
+			var messageHash = sha256.Sum256([]byte(fmt.Sprintf("message %d", i)))
+			sig, err := SignV2(key, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Every other signature is altered, so that half are invalid
+			if i%2 == 1 {
+				sig.S = new(big.Int).Add(sig.S, big.NewInt(int64(i)))
+			}
+			if got, want := verifier.Verify(sig, messageHash[:]), VerifyV2(sig, pub, messageHash[:]); got != want || want != (i%2 == 0) {
+				t.Fatalf("%s message %d: KeyVerifier.Verify = %v, want %v of VerifyV2", curve.Params().Name, i, got, want)
+			}
+		}
+
+		// The public key is copied, so later changes by the caller do not
+		// affect the verifier
+		var owned = PublicKey{X: new(big.Int).Set(pub.X), Y: new(big.Int).Set(pub.Y), Curve: curve}
+		copied, err := VerifierForKey(owned)
+		if err != nil {
+			t.Fatal(err)
+		}
+		owned.X.Add(owned.X, big.NewInt(1))
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !copied.Verify(sig, testMessageHash[:]) {
+			t.Errorf("%s: KeyVerifier affected by a change of the caller's public key", curve.Params().Name)
+		}
+	}
+}
+
+func TestVerifierForKeyInvalid(t *testing.T) {
+	var key = generateKey(t, Secp256k1())
+	var tests = []struct {
+		name string
+		pub  PublicKey
+	}{
+		{"no curve", PublicKey{X: key.PublicX, Y: key.PublicY}},
+		{"identity", PublicKey{X: new(big.Int), Y: new(big.Int), Curve: key.Curve}},
+		{"off the curve", PublicKey{X: key.PublicX, Y: new(big.Int).Add(key.PublicY, big.NewInt(1)), Curve: key.Curve}},
+	}
+	for _, test := range tests {
+		if verifier, err := VerifierForKey(test.pub); err == nil {
+			t.Errorf("%s: VerifierForKey = %v, want an error", test.name, verifier)
+		}
+	}
+}
+
+func TestKeyVerifierConcurrent(t *testing.T) {
+	var key = generateKey(t, Secp256k1())
+	verifier, err := VerifierForKey(key.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := SignV2(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var failures = make(chan int, 32)
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				if !verifier.Verify(sig, testMessageHash[:]) {
+					failures <- g
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(failures)
+	for g := range failures {
+		t.Errorf("goroutine %d: KeyVerifier rejected a valid signature", g)
+	}
+}
@@ -0,0 +1,82 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// Recovers the public key of the signer from signature (r, s) and the message
+// hash. The recovery id selects which of the (up to four) points R = kG could
+// have produced r: bit 0 is the parity of the y-coordinate of R, and bit 1 is
+// set when the x-coordinate of R is r + N rather than r. Given R, the public
+// key is calculated as Q = r^-1 (sR - zG)
+func RecoverPublicKey(sig Signature, recid int, curve elliptic.Curve, messageHash []byte) (x, y *big.Int, err error) {
+	var params = curve.Params()
+	var n = params.N
+
+	if recid < 0 || recid > 3 {
+		return nil, nil, errors.New("Error: Invalid recovery id, must be between 0 and 3")
+	}
+	if !IsValidScalar(sig.R, curve) || !IsValidScalar(sig.S, curve) {
+		return nil, nil, errors.New("Error: Invalid signature, r and s must be within [1, N-1]")
+	}
+
+	// x-coordinate of R is r or r + N
+	var rx = new(big.Int).Set(sig.R)
+	if recid&2 != 0 {
+		rx.Add(rx, n)
+	}
+	if rx.Cmp(params.P) >= 0 {
+		return nil, nil, errors.New("Error: Invalid recovery id, x-coordinate of R outside of the field")
+	}
+
+	// y-coordinate of R from the curve equation and the parity bit
+	var compressedR = make([]byte, 1+(params.BitSize+7)/8)
+	compressedR[0] = byte(2 + recid&1)
+	rx.FillBytes(compressedR[1:])
+	Rx, Ry, err := UnmarshalCompressed(curve, compressedR)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Q = r^-1 (sR - zG) = (-z/r)G + (s/r)R
+	var invR = inverse(sig.R, n)
+	var u1 = new(big.Int).Neg(HashToInt(messageHash, curve))
+	u1 = modN(u1.Mul(u1, invR), curve)
+	var u2 = modN(new(big.Int).Mul(sig.S, invR), curve)
+
+	u1Gx, u1Gy := curve.ScalarBaseMult(u1.Bytes())
+	u2Rx, u2Ry := curve.ScalarMult(Rx, Ry, u2.Bytes())
+	x, y = curve.Add(u1Gx, u1Gy, u2Rx, u2Ry)
+
+	if err = validatePublicPoint(curve, x, y); err != nil {
+		return nil, nil, err
+	}
+
+	return x, y, nil
+}
+
+// Signs the message hash as per SignV2, returning along with the signature
+// the recovery id for which RecoverPublicKey returns the public key of key.
+// s is low-s normalized, which replaces R with -R and so flips the parity bit
+// of the recovery id. Bit 1 is set when the x-coordinate of R is at or above
+// N, which happens with negligible probability on curves such as secp256k1
+// where N is close to P
+func SignRecoverable(key Key, messageHash []byte) (r, s *big.Int, recid int, err error) {
+	r, s, Rx, Ry, err := SignFull(key, messageHash)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	recid = int(Ry.Bit(0))
+	if Rx.Cmp(key.Curve.Params().N) >= 0 {
+		recid |= 2
+	}
+	if low := NormalizeS(s, key.Curve); low.Cmp(s) != 0 {
+		s = low
+		recid ^= 1
+	}
+
+	return r, s, recid, nil
+}
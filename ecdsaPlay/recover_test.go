@@ -0,0 +1,120 @@
+package ecdsaplay
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestRecoverPublicKey(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		for i := 0; i < 10; i++ {
+			r, s, Rx, Ry, err := SignFull(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var recid = int(Ry.Bit(0))
+			if Rx.Cmp(curve.Params().N) >= 0 {
+				recid |= 2
+			}
+
+			x, y, err := RecoverPublicKey(Signature{R: r, S: s}, recid, curve, testMessageHash[:])
+			if err != nil {
+				t.Fatalf("%s: RecoverPublicKey: %v", curve.Params().Name, err)
+			}
+			if x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+				t.Fatalf("%s: recovered (%x, %x), want (%x, %x)", curve.Params().Name, x, y, key.PublicX, key.PublicY)
+			}
+
+			// The other parity gives a key for which the signature verifies
+			// as well, but not the signer's
+			x, y, err = RecoverPublicKey(Signature{R: r, S: s}, recid^1, curve, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Cmp(key.PublicX) == 0 && y.Cmp(key.PublicY) == 0 {
+				t.Fatalf("%s: both parities recovered the public key", curve.Params().Name)
+			}
+			if !Verify(r, s, x, y, curve, testMessageHash[:]) {
+				t.Fatalf("%s: signature does not verify under the other recovered key", curve.Params().Name)
+			}
+		}
+	}
+}
+
+func TestSignRecoverable(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+
+		// Many random messages on secp256k1, for which recovery matters most
+		var count = 20
+		if curve == Secp256k1() {
+			count = 100
+		}
+		for i := 0; i < count; i++ {
+			var message = make([]byte, 32)
+			if _, err := rand.Read(message); err != nil {
+				t.Fatal(err)
+			}
+			var messageHash = sha256.Sum256(message)
+
+			r, s, recid, err := SignRecoverable(key, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if NormalizeS(s, curve).Cmp(s) != 0 {
+				t.Fatalf("%s: s = %x is not low-s normalized", curve.Params().Name, s)
+			}
+			if recid < 0 || recid > 3 {
+				t.Fatalf("%s: recid = %d outside of [0, 3]", curve.Params().Name, recid)
+			}
+			if !Verify(r, s, key.PublicX, key.PublicY, curve, messageHash[:]) {
+				t.Fatalf("%s: signature of SignRecoverable does not verify", curve.Params().Name)
+			}
+
+			x, y, err := RecoverPublicKey(Signature{R: r, S: s}, recid, curve, messageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Cmp(key.PublicX) != 0 || y.Cmp(key.PublicY) != 0 {
+				t.Fatalf("%s message %x: recid %d recovered another key", curve.Params().Name, message, recid)
+			}
+
+			// The other parity recovers a different key
+			if x, y, err := RecoverPublicKey(Signature{R: r, S: s}, recid^1, curve, messageHash[:]); err == nil && x.Cmp(key.PublicX) == 0 && y.Cmp(key.PublicY) == 0 {
+				t.Fatalf("%s: recid %d recovered the key as well", curve.Params().Name, recid^1)
+			}
+		}
+	}
+}
+
+func TestRecoverPublicKeyInvalid(t *testing.T) {
+	var curve = nistCurves[1]
+	var key = generateKey(t, curve)
+	sig, err := SignV2(key, testMessageHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name  string
+		sig   Signature
+		recid int
+	}{
+		{"recid -1", sig, -1},
+		{"recid 4", sig, 4},
+		{"r = 0", Signature{R: big.NewInt(0), S: sig.S}, 0},
+		{"s = N", Signature{R: sig.R, S: new(big.Int).Set(curve.Params().N)}, 0},
+		{"missing r", Signature{S: sig.S}, 0},
+		// r + N lies beyond P on P-256 for all but a negligible share of r
+		{"r + N >= P", Signature{R: new(big.Int).Sub(curve.Params().N, big.NewInt(1)), S: sig.S}, 2},
+	}
+
+	for _, test := range tests {
+		if x, y, err := RecoverPublicKey(test.sig, test.recid, curve, testMessageHash[:]); err == nil {
+			t.Errorf("%s: RecoverPublicKey = (%x, %x), want an error", test.name, x, y)
+		}
+	}
+}
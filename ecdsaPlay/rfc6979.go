@@ -0,0 +1,168 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"math/big"
+)
+
+// Signature = (r, s) computed exactly as in Sign, except that k is derived
+// deterministically from the private key and the message hash using the
+// HMAC-DRBG construction of section 3.2 of RFC 6979. Identical (key, hash)
+// inputs always produce identical signatures and no random number generator
+// is consulted
+func SignDeterministic(key Key, messageHash []byte) (r, s *big.Int, err error) {
+	return SignDeterministicWithEntropy(key, messageHash, nil)
+}
+
+// Signature as per SignDeterministic, with the extra bytes mixed into the
+// HMAC-DRBG as the additional data k' of section 3.6 of RFC 6979. Fresh
+// random extra bytes give hedged signatures, which resist fault attacks on
+// the deterministic derivation and stay safe should the random number
+// generator fail, as k still depends on the private key and message.
+// Identical extra bytes give identical signatures; an empty extra gives the
+// signature of SignDeterministic
+func SignDeterministicWithEntropy(key Key, messageHash []byte, extra []byte) (r, s *big.Int, err error) {
+	if err = checkPrivateKey(key); err != nil {
+		return nil, nil, err
+	}
+	if err = checkSigningHashLength(key.Curve, messageHash); err != nil {
+		return nil, nil, err
+	}
+
+	var nonces = newDeterministicNonces(key, messageHash, extra)
+
+	for {
+		r, s = signWithK(key, messageHash, nonces.next())
+		if r.Sign() != 0 && s.Sign() != 0 {
+			return r, s, nil
+		}
+	}
+}
+
+// HMAC-DRBG state as described in section 3.2 of RFC 6979
+type deterministicNonces struct {
+	curve   elliptic.Curve
+	n       *big.Int
+	hash    func() hash.Hash
+	v, k    []byte
+	started bool
+}
+
+// Steps a. through f. of section 3.2 of RFC 6979; where, x = private key,
+// h1 = hash of the message to be signed and k' = extra, the additional data
+// of section 3.6, which is empty for plain RFC 6979
+func newDeterministicNonces(key Key, messageHash []byte, extra []byte) *deterministicNonces {
+	var d = &deterministicNonces{
+		curve: key.Curve,
+		n:     key.Curve.Params().N,
+		hash:  hashForDigest(key, messageHash),
+	}
+	var hLen = d.hash().Size()
+
+	// V = 0x01 0x01 0x01 ... 0x01 and K = 0x00 0x00 0x00 ... 0x00
+	d.v = make([]byte, hLen)
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+	d.k = make([]byte, hLen)
+
+	var x = d.int2octets(key.Private)
+	var h1 = d.bits2octets(messageHash)
+
+	// K = HMAC_K(V || 0x00 || int2octets(x) || bits2octets(h1) || k') and V = HMAC_K(V)
+	d.k = d.mac(d.k, d.v, []byte{0x00}, x, h1, extra)
+	d.v = d.mac(d.k, d.v)
+
+	// K = HMAC_K(V || 0x01 || int2octets(x) || bits2octets(h1) || k') and V = HMAC_K(V)
+	d.k = d.mac(d.k, d.v, []byte{0x01}, x, h1, extra)
+	d.v = d.mac(d.k, d.v)
+
+	return d
+}
+
+// Step h. of section 3.2 of RFC 6979. Every call after the first one
+// updates K and V before a new candidate is produced, such that a
+// candidate rejected by the caller is never repeated
+func (d *deterministicNonces) next() *big.Int {
+	for {
+		if d.started {
+			// K = HMAC_K(V || 0x00) and V = HMAC_K(V)
+			d.k = d.mac(d.k, d.v, []byte{0x00})
+			d.v = d.mac(d.k, d.v)
+		}
+		d.started = true
+
+		// T = V || V || ... until len(T) >= qlen
+		var t []byte
+		for len(t)*8 < d.n.BitLen() {
+			d.v = d.mac(d.k, d.v)
+			t = append(t, d.v...)
+		}
+
+		// 1 <= k <= N-1
+		var k = d.bits2int(t)
+		if IsValidScalar(k, d.curve) {
+			return k
+		}
+	}
+}
+
+// HMAC_K(data[0] || data[1] || ...)
+func (d *deterministicNonces) mac(key []byte, data ...[]byte) []byte {
+	var m = hmac.New(d.hash, key)
+	for _, b := range data {
+		m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// Leftmost qlen bits of b as a non-negative integer, section 2.3.2 of RFC 6979
+func (d *deterministicNonces) bits2int(b []byte) *big.Int {
+	var x = new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - d.n.BitLen(); excess > 0 {
+		x.Rsh(x, uint(excess))
+	}
+	return x
+}
+
+// x as a big-endian sequence of exactly rlen bytes, section 2.3.3 of RFC 6979
+func (d *deterministicNonces) int2octets(x *big.Int) []byte {
+	var out = make([]byte, (d.n.BitLen()+7)/8)
+	return x.FillBytes(out)
+}
+
+// bits2int(b) mod N as rlen bytes, section 2.3.4 of RFC 6979
+func (d *deterministicNonces) bits2octets(b []byte) []byte {
+	var z = d.bits2int(b)
+	z.Mod(z, d.n)
+	return d.int2octets(z)
+}
+
+// The HMAC hash is the one that produced the message hash, recognised by its
+// length. Unrecognised lengths fall back on the hash matching the curve size
+func hashForDigest(key Key, messageHash []byte) func() hash.Hash {
+	var size = len(messageHash)
+	switch size {
+	case sha1.Size, sha256.Size224, sha256.Size, sha512.Size384, sha512.Size:
+	default:
+		size = (key.Curve.Params().N.BitLen() + 7) / 8
+	}
+
+	switch {
+	case size <= sha1.Size:
+		return sha1.New
+	case size <= sha256.Size224:
+		return sha256.New224
+	case size <= sha256.Size:
+		return sha256.New
+	case size <= sha512.Size384:
+		return sha512.New384
+	default:
+		return sha512.New
+	}
+}
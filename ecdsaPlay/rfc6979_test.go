@@ -0,0 +1,178 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"fmt"
+	"testing"
+)
+
+// Test vectors of appendix A.2.5 of RFC 6979 for P-256 with the private key
+// x = C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721
+var rfc6979P256Tests = []struct {
+	hash    crypto.Hash
+	message string
+	k, r, s string
+}{
+
+	{crypto.SHA1, "sample",
+		"882905F1227FD620FBF2ABF21244F0BA83D0DC3A9103DBBEE43A1FB858109DB4",
+		"61340C88C3AAEBEB4F6D667F672CA9759A6CCAA9FA8811313039EE4A35471D32",
+		"6D7F147DAC089441BB2E2FE8F7A3FA264B9C475098FDCF6E00D7C996E1B8B7EB"},
+	{crypto.SHA224, "sample",
+		"103F90EE9DC52E5E7FB5132B7033C63066D194321491862059967C715985D473",
+		"53B2FFF5D1752B2C689DF257C04C40A587FABABB3F6FC2702F1343AF7CA9AA3F",
+		"B9AFB64FDC03DC1A131C7D2386D11E349F070AA432A4ACC918BEA988BF75C74C"},
+	{crypto.SHA256, "sample",
+		"A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60",
+		"EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+		"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+	{crypto.SHA384, "sample",
+		"09F634B188CEFD98E7EC88B1AA9852D734D0BC272F7D2A47DECC6EBEB375AAD4",
+		"0EAFEA039B20E9B42309FB1D89E213057CBF973DC0CFC8F129EDDDC800EF7719",
+		"4861F0491E6998B9455193E34E7B0D284DDD7149A74B95B9261F13ABDE940954"},
+	{crypto.SHA512, "sample",
+		"5FA81C63109BADB88C1F367B47DA606DA28CAD69AA22C4FE6AD7DF73A7173AA5",
+		"8496A60B5E9B47C825488827E0495B0E3FA109EC4568FD3F8D1097678EB97F00",
+		"2362AB1ADBE2B8ADF9CB9EDAB740EA6049C028114F2460F96554F61FAE3302FE"},
+	{crypto.SHA1, "test",
+		"8C9520267C55D6B980DF741E56B4ADEE114D84FBFA2E62137954164028632A2E",
+		"0CBCC86FD6ABD1D99E703E1EC50069EE5C0B4BA4B9AC60E409E8EC5910D81A89",
+		"01B9D7B73DFAA60D5651EC4591A0136F87653E0FD780C3B1BC872FFDEAE479B1"},
+	{crypto.SHA224, "test",
+		"669F4426F2688B8BE0DB3A6BD1989BDAEFFF84B649EEB84F3DD26080F667FAA7",
+		"C37EDB6F0AE79D47C3C27E962FA269BB4F441770357E114EE511F662EC34A692",
+		"C820053A05791E521FCAAD6042D40AEA1D6B1A540138558F47D0719800E18F2D"},
+	{crypto.SHA256, "test",
+		"D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0",
+		"F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+		"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	{crypto.SHA384, "test",
+		"16AEFFA357260B04B1DD199693960740066C1A8F3E8EDD79070AA914D361B3B8",
+		"83910E8B48BB0C74244EBDF7F07A1C5413D61472BD941EF3920E623FBCCEBEB6",
+		"8DDBEC54CF8CD5874883841D712142A56A8D0F218F5003CB0296B6B509619F2C"},
+	{crypto.SHA512, "test",
+		"6915D11632ACA3C40D5D51C08DAF9C555933819548784480E93499000D9F0B7F",
+		"461D93F31B6540894788FD206C07CFA0CC35F46FA3C91816FFF1040AD1581A04",
+		"39AF9F15DE0DB8D97E72719C74820D304CE5226E32DEDAE67519E840D1194E55"},
+}
+
+// Private key of the test vectors for P-256 of appendix A.2.5 of RFC 6979
+func rfc6979P256Key(t *testing.T) Key {
+	t.Helper()
+	var key = Key{Private: hexInt("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"), Curve: elliptic.P256()}
+	key.PublicX, key.PublicY = key.Curve.ScalarBaseMult(key.Private.Bytes())
+	if key.PublicX.Cmp(hexInt("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6")) != 0 ||
+		key.PublicY.Cmp(hexInt("7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299")) != 0 {
+		t.Fatalf("public key (%x, %x) differs from RFC 6979", key.PublicX, key.PublicY)
+	}
+	return key
+}
+
+func TestSignDeterministicRFC6979(t *testing.T) {
+	var key = rfc6979P256Key(t)
+
+	for _, test := range rfc6979P256Tests {
+		var h = test.hash.New()
+		h.Write([]byte(test.message))
+		var messageHash = h.Sum(nil)
+
+		if k := newDeterministicNonces(key, messageHash, nil).next(); k.Cmp(hexInt(test.k)) != 0 {
+			t.Errorf("%v %q: k = %X, want %s", test.hash, test.message, k, test.k)
+		}
+
+		r, s, err := SignDeterministic(key, messageHash)
+		if err != nil {
+			t.Fatalf("%v %q: %v", test.hash, test.message, err)
+		}
+		if r.Cmp(hexInt(test.r)) != 0 || s.Cmp(hexInt(test.s)) != 0 {
+			t.Errorf("%v %q: (r, s) = (%X, %X), want (%s, %s)", test.hash, test.message, r, s, test.r, test.s)
+		}
+		if !Verify(r, s, key.PublicX, key.PublicY, key.Curve, messageHash) {
+			t.Errorf("%v %q: signature does not verify", test.hash, test.message)
+		}
+	}
+}
+
+func TestSignDeterministicRepeatable(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+
+		r1, s1, err := SignDeterministic(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2, s2, err := SignDeterministic(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r1.Cmp(r2) != 0 || s1.Cmp(s2) != 0 {
+			t.Errorf("%s: two signatures of the same hash differ", curve.Params().Name)
+		}
+	}
+}
+
+func TestSignDeterministicWithEntropy(t *testing.T) {
+	var key = rfc6979P256Key(t)
+	var messageHash = sha256.Sum256([]byte("sample"))
+
+	// k of the key and SHA-256 "sample" above with k' = "extra entropy" mixed
+	// into the HMAC-DRBG, as computed independently of this package
+	var extraK = "666ECEF0DEC9078DE30721D99B958729AD493E4A57D1C084052EC4EF917D7EEE"
+	var tests = []struct {
+		extra []byte
+		k     string
+	}{
+		{nil, rfc6979P256Tests[2].k},
+		{[]byte{}, rfc6979P256Tests[2].k},
+		{[]byte("extra entropy"), extraK},
+	}
+
+	for _, test := range tests {
+		if k := newDeterministicNonces(key, messageHash[:], test.extra).next(); k.Cmp(hexInt(test.k)) != 0 {
+			t.Errorf("extra %q: k = %X, want %s", test.extra, k, test.k)
+		}
+	}
+
+	// An empty extra gives the signature of SignDeterministic
+	r, s, err := SignDeterministicWithEntropy(key, messageHash[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(hexInt(rfc6979P256Tests[2].r)) != 0 || s.Cmp(hexInt(rfc6979P256Tests[2].s)) != 0 {
+		t.Errorf("no extra: (r, s) = (%X, %X), want (%s, %s)", r, s, rfc6979P256Tests[2].r, rfc6979P256Tests[2].s)
+	}
+
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		plainR, _, err := SignDeterministic(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var extras = [][]byte{[]byte("extra entropy"), []byte("other entropy"), {0x00}, make([]byte, 64)}
+		var seen = map[string]string{plainR.String(): "no extra"}
+		for _, extra := range extras {
+			r1, s1, err := SignDeterministicWithEntropy(key, testMessageHash[:], extra)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r2, s2, err := SignDeterministicWithEntropy(key, testMessageHash[:], append([]byte{}, extra...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r1.Cmp(r2) != 0 || s1.Cmp(s2) != 0 {
+				t.Errorf("%s: extra %x: two signatures differ", curve.Params().Name, extra)
+			}
+			if other, ok := seen[r1.String()]; ok {
+				t.Errorf("%s: extra %x gives the r of %s", curve.Params().Name, extra, other)
+			}
+			seen[r1.String()] = fmt.Sprintf("extra %x", extra)
+			if !Verify(r1, s1, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+				t.Errorf("%s: extra %x: signature does not verify", curve.Params().Name, extra)
+			}
+		}
+	}
+}
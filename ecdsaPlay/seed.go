@@ -0,0 +1,62 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+)
+
+// HKDF salt separating keys derived by GenerateKeyFromSeed from other uses
+// of the same seed
+var seedSalt = []byte("ecdsaplay GenerateKeyFromSeed")
+
+// Generates Public/Private key pair deterministically from seed. The seed is
+// expanded with HKDF-SHA256 (RFC 5869) into a candidate private key which is
+// accepted when it lies within [1, N-1]. Any other candidate is rejected and
+// a fresh one is expanded using an incremented counter, so identical seeds
+// always return identical keys
+func GenerateKeyFromSeed(curve elliptic.Curve, seed []byte) (Key, error) {
+	if len(seed) == 0 {
+		return Key{}, errors.New("Error: Invalid seed, empty")
+	}
+
+	var n = curve.Params().N
+	var byteLen = (n.BitLen() + 7) / 8
+	var prk = hkdfExtract(seedSalt, seed)
+
+	for counter := uint32(0); ; counter++ {
+		var info = append([]byte(curve.Params().Name), byte(counter>>24), byte(counter>>16), byte(counter>>8), byte(counter))
+		var candidate = ConcatenateBytes(hkdfExpand(prk, info, byteLen))
+
+		// Excess bits beyond the bit length of N are dropped, as in RFC 6979
+		candidate.Rsh(candidate, uint(byteLen*8-n.BitLen()))
+
+		if IsValidScalar(candidate, curve) {
+			return NewKeyFromScalar(curve, candidate)
+		}
+	}
+}
+
+// HKDF-Extract(salt, IKM) = HMAC-SHA256(salt, IKM), section 2.2 of RFC 5869
+func hkdfExtract(salt, ikm []byte) []byte {
+	var m = hmac.New(sha256.New, salt)
+	m.Write(ikm)
+	return m.Sum(nil)
+}
+
+// HKDF-Expand(PRK, info, L), section 2.3 of RFC 5869; where,
+// T(i) = HMAC-SHA256(PRK, T(i-1) || info || i) and OKM = T(1) || T(2) || ...
+func hkdfExpand(prk, info []byte, length int) []byte {
+	var okm []byte
+	var t []byte
+	for i := byte(1); len(okm) < length; i++ {
+		var m = hmac.New(sha256.New, prk)
+		m.Write(t)
+		m.Write(info)
+		m.Write([]byte{i})
+		t = m.Sum(nil)
+		okm = append(okm, t...)
+	}
+	return okm[:length]
+}
@@ -0,0 +1,81 @@
+package ecdsaplay
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGenerateKeyFromSeedDeterministic(t *testing.T) {
+	var seed = []byte("correct horse battery staple")
+	for _, curve := range SupportedCurves() {
+		key1, err := GenerateKeyFromSeed(curve, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key2, err := GenerateKeyFromSeed(curve, append([]byte{}, seed...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key1.Private.Cmp(key2.Private) != 0 || key1.PublicX.Cmp(key2.PublicX) != 0 || key1.PublicY.Cmp(key2.PublicY) != 0 {
+			t.Fatalf("%s: two keys from the same seed differ", curve.Params().Name)
+		}
+		if err := key1.Validate(); err != nil {
+			t.Fatalf("%s: %v", curve.Params().Name, err)
+		}
+
+		other, err := GenerateKeyFromSeed(curve, []byte("correct horse battery stapler"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other.Private.Cmp(key1.Private) == 0 {
+			t.Fatalf("%s: keys from distinct seeds are equal", curve.Params().Name)
+		}
+	}
+
+	if _, err := GenerateKeyFromSeed(nistCurves[1], nil); err == nil {
+		t.Error("GenerateKeyFromSeed accepted an empty seed")
+	}
+}
+
+func TestGenerateKeyFromSeedRejects(t *testing.T) {
+	// About half of the first candidates are at or above N on the toy curve,
+	// for which the candidate of counter 1 is to be used instead
+	var curve = toyCurve(t)
+	var n = curve.Params().N
+
+	var rejected int
+	for i := 0; i < 64; i++ {
+		var seed = []byte(fmt.Sprintf("seed %d", i))
+		var prk = hkdfExtract(seedSalt, seed)
+		var candidate = func(counter byte) uint64 {
+			var b = hkdfExpand(prk, append([]byte(curve.Params().Name), 0, 0, 0, counter), 2)
+			return uint64(b[0])<<8 | uint64(b[1])
+		}
+
+		key, err := GenerateKeyFromSeed(curve, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsValidScalar(key.Private, curve) {
+			t.Fatalf("%q: private key %v outside of [1, N-1]", seed, key.Private)
+		}
+
+		var first = candidate(0)
+		if first != 0 && first < n.Uint64() {
+			if key.Private.Uint64() != first {
+				t.Fatalf("%q: private key %v, want the first candidate %v", seed, key.Private, first)
+			}
+			continue
+		}
+		rejected++
+		if key.Private.Uint64() == first {
+			t.Fatalf("%q: out of range candidate %v was not rejected", seed, first)
+		}
+		if second := candidate(1); second != 0 && second < n.Uint64() && key.Private.Uint64() != second {
+			t.Fatalf("%q: private key %v, want the second candidate %v", seed, key.Private, second)
+		}
+	}
+	if rejected == 0 {
+		t.Fatal("no seed had its first candidate rejected")
+	}
+}
@@ -0,0 +1,86 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+)
+
+// Signature = (r, s) as produced by Sign
+type Signature struct {
+	R, S *big.Int
+}
+
+// r and s in hex for logging, e.g. Signature{R: 1f0c..., S: 7a3b...}. The
+// recovery id of RecoverPublicKey is not part of a Signature, so it is shown
+// by neither this nor any other format
+func (sig Signature) String() string {
+	return fmt.Sprintf("Signature{R: %x, S: %x}", sig.R, sig.S)
+}
+
+// Public key point (X, Y) along with the curve it belongs to
+type PublicKey struct {
+	X, Y  *big.Int
+	Curve elliptic.Curve
+}
+
+// Public half of the key pair
+func (k Key) PublicKey() PublicKey {
+	return PublicKey{X: k.PublicX, Y: k.PublicY, Curve: k.Curve}
+}
+
+// Signs the message hash as per Sign and returns the result as a Signature.
+// The message hash must be non-empty, or ErrInvalidHashLength is returned,
+// and at most MaxMessageHashSize bytes long, or the byte size of N if that
+// is larger, or ErrHashTooLong is returned
+func SignV2(key Key, messageHash []byte) (Signature, error) {
+	r, s, err := Sign(key, messageHash)
+	if err != nil {
+		return Signature{}, err
+	}
+	return Signature{R: r, S: s}, nil
+}
+
+// Verifies the signature against the public key as per Verify. N and G are
+// taken from the curve of the public key, so the two cannot be mismatched;
+// a public key without a curve, or whose point is not on its curve (e.g. a
+// P-256 point labelled as P-384), fails verification
+func VerifyV2(sig Signature, pub PublicKey, messageHash []byte) bool {
+	if pub.Curve == nil {
+		return false
+	}
+	return Verify(sig.R, sig.S, pub.X, pub.Y, pub.Curve, messageHash)
+}
+
+// Byte length of a signature encoded by EncodeSignatureFixed, i.e. twice the
+// byte size of N, e.g. 64 bytes for P-256
+func SignatureByteLen(curve elliptic.Curve) int {
+	return 2 * scalarByteLen(curve)
+}
+
+// Encodes the signature as r || s; where, r and s are each left-padded with
+// zeros to the byte size of N, as used by JWS (RFC 7518) and Ethereum. A
+// signature with r or s missing, negative or too large to fit yields nil
+func EncodeSignatureFixed(sig Signature, curve elliptic.Curve) []byte {
+	var byteLen = scalarByteLen(curve)
+	for _, value := range []*big.Int{sig.R, sig.S} {
+		if value == nil || value.Sign() < 0 || value.BitLen() > byteLen*8 {
+			return nil
+		}
+	}
+
+	var b = make([]byte, 2*byteLen)
+	sig.R.FillBytes(b[:byteLen])
+	sig.S.FillBytes(b[byteLen:])
+	return b
+}
+
+// Decodes a signature encoded by EncodeSignatureFixed, which must be exactly
+// SignatureByteLen(curve) bytes long
+func DecodeSignatureFixed(b []byte, curve elliptic.Curve) (Signature, error) {
+	var byteLen = scalarByteLen(curve)
+	if len(b) != 2*byteLen {
+		return Signature{}, fmt.Errorf("Error: Invalid signature, must be %d bytes", 2*byteLen)
+	}
+	return Signature{R: new(big.Int).SetBytes(b[:byteLen]), S: new(big.Int).SetBytes(b[byteLen:])}, nil
+}
@@ -0,0 +1,185 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestSignV2VerifyV2(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		var pub = key.PublicKey()
+		if pub.X != key.PublicX || pub.Y != key.PublicY || pub.Curve != curve {
+			t.Fatalf("%s: PublicKey() = %v, want the public half of the key", curve.Params().Name, pub)
+		}
+
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Delegates to Verify, which accepts the same (r, s)
+		if !Verify(sig.R, sig.S, key.PublicX, key.PublicY, curve, testMessageHash[:]) {
+			t.Errorf("%s: Verify rejected the signature of SignV2", curve.Params().Name)
+		}
+
+		var tests = []struct {
+			name string
+			sig  Signature
+			pub  PublicKey
+			want bool
+		}{
+			{"valid", sig, pub, true},
+			{"R and S swapped", Signature{R: sig.S, S: sig.R}, pub, false},
+			{"missing S", Signature{R: sig.R}, pub, false},
+			{"zero value", Signature{}, pub, false},
+			{"X and Y swapped", sig, PublicKey{X: pub.Y, Y: pub.X, Curve: curve}, false},
+		}
+		for _, test := range tests {
+			if got := VerifyV2(test.sig, test.pub, testMessageHash[:]); got != test.want {
+				t.Errorf("%s %s: VerifyV2 = %v, want %v", curve.Params().Name, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestVerifyV2CurveMismatch(t *testing.T) {
+	var curves = append(nistCurves, Secp256k1())
+	for _, curve := range curves {
+		var key = generateKey(t, curve)
+		sig, err := SignV2(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The point of the key, labelled as on any other curve, is not on
+		// that curve, e.g. a P-256 public key verified with P-384 params
+		for _, other := range append(curves, nil) {
+			if other == curve {
+				continue
+			}
+			var pub = key.PublicKey()
+			pub.Curve = other
+			if other == nil {
+				if VerifyV2(sig, pub, testMessageHash[:]) {
+					t.Errorf("%s: VerifyV2 accepted the key without a curve", curve.Params().Name)
+				}
+				continue
+			}
+			if VerifyV2(sig, pub, testMessageHash[:]) {
+				t.Errorf("%s: VerifyV2 accepted the key labelled as %s", curve.Params().Name, other.Params().Name)
+			}
+
+			// r and s fitting the order of the other curve reach the point check
+			var want = ErrPointNotOnCurve
+			if !IsValidScalar(sig.R, other) {
+				want = ErrROutOfRange
+			} else if !IsValidScalar(sig.S, other) {
+				want = ErrSOutOfRange
+			}
+			if _, err := VerifyDetailed(sig.R, sig.S, pub.X, pub.Y, other, testMessageHash[:]); !errors.Is(err, want) {
+				t.Errorf("%s: VerifyDetailed on %s = %v, want %v", curve.Params().Name, other.Params().Name, err, want)
+			}
+		}
+	}
+}
+
+func TestSignatureByteLen(t *testing.T) {
+	var tests = []struct {
+		curve elliptic.Curve
+		want  int
+	}{
+		{elliptic.P224(), 56},
+		{elliptic.P256(), 64},
+		{elliptic.P384(), 96},
+		{elliptic.P521(), 132},
+		{Secp256k1(), 64},
+	}
+
+	for _, test := range tests {
+		if got := SignatureByteLen(test.curve); got != test.want {
+			t.Errorf("%s: SignatureByteLen = %d, want %d", test.curve.Params().Name, got, test.want)
+		}
+
+		var key = generateKey(t, test.curve)
+		for i := 0; i < 10; i++ {
+			sig, err := SignV2(key, testMessageHash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixed = EncodeSignatureFixed(sig, test.curve)
+			if len(fixed) != test.want {
+				t.Fatalf("%s: len(EncodeSignatureFixed) = %d, want %d", test.curve.Params().Name, len(fixed), test.want)
+			}
+			decoded, err := DecodeSignatureFixed(fixed, test.curve)
+			if err != nil || decoded.R.Cmp(sig.R) != 0 || decoded.S.Cmp(sig.S) != 0 {
+				t.Fatalf("%s: DecodeSignatureFixed = (%v, %v), want %v", test.curve.Params().Name, decoded, err, sig)
+			}
+		}
+	}
+}
+
+func TestEncodeSignatureFixedPadding(t *testing.T) {
+	var curve = elliptic.P256()
+
+	var tests = []struct {
+		name string
+		sig  Signature
+		want []byte
+	}{
+		{"r = 1, s = 2", Signature{R: big.NewInt(1), S: big.NewInt(2)},
+			append(append(make([]byte, 31), 1), append(make([]byte, 31), 2)...)},
+		{"short r", Signature{R: big.NewInt(0x1234), S: new(big.Int).SetBytes(bytes.Repeat([]byte{0xab}, 32))},
+			append(append(make([]byte, 30), 0x12, 0x34), bytes.Repeat([]byte{0xab}, 32)...)},
+		{"31 byte s", Signature{R: new(big.Int).SetBytes(bytes.Repeat([]byte{0xcd}, 32)), S: new(big.Int).SetBytes(bytes.Repeat([]byte{0xef}, 31))},
+			append(append(bytes.Repeat([]byte{0xcd}, 32), 0x00), bytes.Repeat([]byte{0xef}, 31)...)},
+	}
+
+	for _, test := range tests {
+		var fixed = EncodeSignatureFixed(test.sig, curve)
+		if !bytes.Equal(fixed, test.want) {
+			t.Errorf("%s: EncodeSignatureFixed = %x, want %x", test.name, fixed, test.want)
+		}
+		decoded, err := DecodeSignatureFixed(fixed, curve)
+		if err != nil || decoded.R.Cmp(test.sig.R) != 0 || decoded.S.Cmp(test.sig.S) != 0 {
+			t.Errorf("%s: DecodeSignatureFixed = (%v, %v), want %v", test.name, decoded, err, test.sig)
+		}
+	}
+
+	// Values missing, negative or too large to fit yield nil
+	var tooLarge = new(big.Int).Lsh(big.NewInt(1), 256)
+	for _, sig := range []Signature{{S: big.NewInt(1)}, {R: big.NewInt(-1), S: big.NewInt(1)}, {R: big.NewInt(1), S: tooLarge}} {
+		if fixed := EncodeSignatureFixed(sig, curve); fixed != nil {
+			t.Errorf("EncodeSignatureFixed(%v) = %x, want nil", sig, fixed)
+		}
+	}
+	for _, n := range []int{0, 63, 65} {
+		if _, err := DecodeSignatureFixed(make([]byte, n), curve); err == nil {
+			t.Errorf("DecodeSignatureFixed of %d bytes gave no error", n)
+		}
+	}
+}
+
+func TestSignatureString(t *testing.T) {
+	var tests = []struct {
+		sig  Signature
+		want string
+	}{
+		{Signature{R: big.NewInt(0x1f0c), S: big.NewInt(0x7a3b)}, "Signature{R: 1f0c, S: 7a3b}"},
+		{Signature{R: hexInt("EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716"), S: big.NewInt(1)},
+			"Signature{R: efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716, S: 1}"},
+		{Signature{}, "Signature{R: <nil>, S: <nil>}"},
+	}
+
+	for _, test := range tests {
+		if got := test.sig.String(); got != test.want {
+			t.Errorf("String = %q, want %q", got, test.want)
+		}
+		if got := fmt.Sprint(test.sig); got != test.want {
+			t.Errorf("Sprint = %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ecdsaplay
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"io"
+)
+
+// Key satisfies crypto.Signer so that it can be handed to the standard
+// library, e.g. as the PrivateKey of a tls.Certificate
+var _ crypto.Signer = Key{}
+
+// Public key as the standard library *ecdsa.PublicKey built from PublicX,
+// PublicY and Curve
+func (k Key) Public() crypto.PublicKey {
+	return &ecdsa.PublicKey{Curve: k.Curve, X: k.PublicX, Y: k.PublicY}
+}
+
+// Signs digest as per Sign and returns the DER encoded signature, as required
+// by crypto.Signer. The per-message secret is read from random, or from
+// crypto/rand when random is nil. opts is ignored since the digest is
+// expected to be hashed already
+func (k Key) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	r, s, _, _, err := signPoint(context.Background(), random, k, digest)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSignatureDER(r, s)
+}
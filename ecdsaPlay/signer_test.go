@@ -0,0 +1,38 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestSignerVerifyASN1(t *testing.T) {
+	// SHA-224 is no longer than N on any of the curves
+	var messageHash = sha256.Sum224([]byte("Take the red pill!"))
+	for _, curve := range nistCurves {
+		var signer crypto.Signer = generateKey(t, curve)
+
+		pub, ok := signer.Public().(*ecdsa.PublicKey)
+		if !ok {
+			t.Fatalf("%s: Public() = %T, want *ecdsa.PublicKey", curve.Params().Name, signer.Public())
+		}
+
+		for _, random := range []io.Reader{rand.Reader, nil} {
+			signature, err := signer.Sign(random, messageHash[:], crypto.SHA224)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ecdsa.VerifyASN1(pub, messageHash[:], signature) {
+				t.Errorf("%s: ecdsa.VerifyASN1 rejected the signature of crypto.Signer", curve.Params().Name)
+			}
+
+			var otherHash = sha256.Sum224([]byte("Take the green pill!"))
+			if ecdsa.VerifyASN1(pub, otherHash[:], signature) {
+				t.Errorf("%s: ecdsa.VerifyASN1 accepted the signature for another hash", curve.Params().Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ecdsaplay
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Counts of the operations performed by VerifyWithStats, accumulated over
+// every call the same VerifyStats is passed to. The counters are not
+// synchronized, so a VerifyStats must not be shared by concurrent calls
+type VerifyStats struct {
+	// Modular inversions of s
+	Inversions int
+
+	// Independent scalar multiplications uG and vP, as performed by Verify
+	ScalarMults int
+
+	// Point additions combining the results of two scalar multiplications
+	PointAdds int
+
+	// Joint calculations of uG + vP in a single pass, as performed by
+	// VerifyFast
+	DoubleScalarMults int
+}
+
+// Verification as per Verify, or as per VerifyFast when fast is set, adding
+// the operations performed to stats, which may be nil. This allows the two
+// ways of calculating uG + vP to be compared. A signature rejected before
+// uG + vP is calculated, e.g. as r is out of range, adds no scalar
+// multiplications
+func VerifyWithStats(r, s, publicKeyX, publicKeyY *big.Int, curve elliptic.Curve, messageHash []byte, fast bool, stats *VerifyStats) bool {
+	if stats == nil {
+		stats = new(VerifyStats)
+	}
+
+	var invS *big.Int
+	if IsValidScalar(s, curve) {
+		invS = inverse(s, curve.Params().N)
+		stats.Inversions++
+	}
+
+	var combine linearCombination = func(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
+		stats.ScalarMults += 2
+		stats.PointAdds++
+		return scalarMultCombination(curve, u, v, publicKeyX, publicKeyY)
+	}
+	if fast {
+		combine = func(curve elliptic.Curve, u, v, publicKeyX, publicKeyY *big.Int) (x, y *big.Int) {
+			stats.DoubleScalarMults++
+			return doubleScalarMult(curve, u, v, publicKeyX, publicKeyY)
+		}
+	}
+
+	valid, _ := verify(r, s, invS, publicKeyX, publicKeyY, curve, messageHash, combine)
+	return valid
+}
@@ -0,0 +1,57 @@
+package ecdsaplay
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestVerifyWithStats(t *testing.T) {
+	for _, curve := range append(nistCurves, Secp256k1()) {
+		var key = generateKey(t, curve)
+		r, s, err := Sign(key, testMessageHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var otherHash = testMessageHash
+		otherHash[0] ^= 1
+
+		var tests = []struct {
+			name  string
+			r, s  *big.Int
+			hash  []byte
+			fast  bool
+			valid bool
+			want  VerifyStats
+		}{
+			{"naive", r, s, testMessageHash[:], false, true, VerifyStats{Inversions: 1, ScalarMults: 2, PointAdds: 1}},
+			{"fast", r, s, testMessageHash[:], true, true, VerifyStats{Inversions: 1, DoubleScalarMults: 1}},
+			{"naive other hash", r, s, otherHash[:], false, false, VerifyStats{Inversions: 1, ScalarMults: 2, PointAdds: 1}},
+			{"fast other hash", r, s, otherHash[:], true, false, VerifyStats{Inversions: 1, DoubleScalarMults: 1}},
+			{"r = 0", new(big.Int), s, testMessageHash[:], false, false, VerifyStats{Inversions: 1}},
+			{"s = 0", r, new(big.Int), testMessageHash[:], true, false, VerifyStats{}},
+		}
+
+		for _, test := range tests {
+			var stats VerifyStats
+			if valid := VerifyWithStats(test.r, test.s, key.PublicX, key.PublicY, curve, test.hash, test.fast, &stats); valid != test.valid {
+				t.Errorf("%s %s: VerifyWithStats = %v, want %v", curve.Params().Name, test.name, valid, test.valid)
+			}
+			if stats != test.want {
+				t.Errorf("%s %s: stats = %+v, want %+v", curve.Params().Name, test.name, stats, test.want)
+			}
+		}
+
+		// Counters accumulate over calls, and nil stats are allowed
+		var stats VerifyStats
+		for i := 0; i < 3; i++ {
+			VerifyWithStats(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:], false, &stats)
+			VerifyWithStats(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:], true, &stats)
+		}
+		if want := (VerifyStats{Inversions: 6, ScalarMults: 6, PointAdds: 3, DoubleScalarMults: 3}); stats != want {
+			t.Errorf("%s: accumulated stats = %+v, want %+v", curve.Params().Name, stats, want)
+		}
+		if !VerifyWithStats(r, s, key.PublicX, key.PublicY, curve, testMessageHash[:], true, nil) {
+			t.Errorf("%s: VerifyWithStats with nil stats rejected the signature", curve.Params().Name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"hash"
+)
+
+// Signs a message written to it in any number of chunks, e.g. by io.Copy from
+// a large file, without buffering the message. Signer implements io.Writer and
+// signs the same hash as SignMessage over the whole message would
+type Signer struct {
+	key  Key
+	hash hash.Hash
+}
+
+// Returns a Signer for key hashing the message with hashFunc, which must be
+// linked into the binary
+func NewSigner(key Key, hashFunc crypto.Hash) (*Signer, error) {
+	if err := checkPrivateKey(key); err != nil {
+		return nil, err
+	}
+	h, err := newHash(hashFunc)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: key, hash: h}, nil
+}
+
+// Adds p to the message; it never returns an error
+func (s *Signer) Write(p []byte) (int, error) {
+	return s.hash.Write(p)
+}
+
+// Signs the hash of everything written so far as per SignV2. Further writes
+// extend the same message
+func (s *Signer) Sign() (Signature, error) {
+	return SignV2(s.key, s.hash.Sum(nil))
+}
+
+// Verifies a signature over a message written to it in any number of chunks,
+// mirroring Signer
+type Verifier struct {
+	pub  PublicKey
+	hash hash.Hash
+}
+
+// Returns a Verifier for pub hashing the message with hashFunc, which must be
+// linked into the binary
+func NewVerifier(pub PublicKey, hashFunc crypto.Hash) (*Verifier, error) {
+	h, err := newHash(hashFunc)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{pub: pub, hash: h}, nil
+}
+
+// Adds p to the message; it never returns an error
+func (v *Verifier) Write(p []byte) (int, error) {
+	return v.hash.Write(p)
+}
+
+// Verifies sig against the hash of everything written so far as per VerifyV2
+func (v *Verifier) Verify(sig Signature) bool {
+	return VerifyV2(sig, v.pub, v.hash.Sum(nil))
+}
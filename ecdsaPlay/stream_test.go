@@ -0,0 +1,91 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/elliptic"
+	"io"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestSignerMatchesOneShot(t *testing.T) {
+	// A few MiB of pseudo-random data, written in chunks of varying sizes
+	var message = make([]byte, 3<<20+17)
+	rand.New(rand.NewSource(1)).Read(message)
+
+	var tests = []struct {
+		curve    elliptic.Curve
+		hashFunc crypto.Hash
+	}{
+		{elliptic.P256(), crypto.SHA256},
+		{elliptic.P384(), crypto.SHA384},
+		{elliptic.P521(), crypto.SHA512},
+		{Secp256k1(), crypto.SHA256},
+	}
+
+	for _, test := range tests {
+		var key = generateKey(t, test.curve)
+
+		// The same k for both signatures, so that they must be equal
+		var buffer = candidateBuffer(test.curve, big.NewInt(0x1234567))
+		setNonceSource(t, bytes.NewReader(append(append([]byte{}, buffer...), buffer...)))
+
+		signer, err := NewSigner(key, test.hashFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for offset, size := 0, 1; offset < len(message); offset, size = offset+size, size*3+1 {
+			var end = offset + size
+			if end > len(message) {
+				end = len(message)
+			}
+			if n, err := signer.Write(message[offset:end]); n != end-offset || err != nil {
+				t.Fatalf("%s: Write = (%d, %v)", test.curve.Params().Name, n, err)
+			}
+		}
+		streamed, err := signer.Sign()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		oneShot, err := SignMessage(key, message, test.hashFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if streamed.R.Cmp(oneShot.R) != 0 || streamed.S.Cmp(oneShot.S) != 0 {
+			t.Fatalf("%s: streamed signature %v, want %v of SignMessage", test.curve.Params().Name, streamed, oneShot)
+		}
+
+		verifier, err := NewVerifier(key.PublicKey(), test.hashFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.Copy(verifier, bytes.NewReader(message)); err != nil {
+			t.Fatal(err)
+		}
+		if !verifier.Verify(streamed) {
+			t.Errorf("%s: Verifier rejected the streamed signature", test.curve.Params().Name)
+		}
+		if _, err := verifier.Write([]byte{0}); err != nil {
+			t.Fatal(err)
+		}
+		if verifier.Verify(streamed) {
+			t.Errorf("%s: Verifier accepted the signature for an extended message", test.curve.Params().Name)
+		}
+	}
+}
+
+func TestNewSignerInvalid(t *testing.T) {
+	var key = generateKey(t, elliptic.P256())
+	if _, err := NewSigner(key, crypto.MD4); err == nil {
+		t.Error("NewSigner with MD4 unavailable gave no error")
+	}
+	if _, err := NewVerifier(key.PublicKey(), crypto.MD4); err == nil {
+		t.Error("NewVerifier with MD4 unavailable gave no error")
+	}
+	if _, err := NewSigner(Key{Curve: elliptic.P256(), Private: big.NewInt(0)}, crypto.SHA256); err == nil {
+		t.Error("NewSigner with a zero private key gave no error")
+	}
+}
@@ -0,0 +1,30 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"fmt"
+)
+
+// Maximum number of key pairs generated by GenerateKeyPairWithPrefix
+const maxVanityAttempts = 1 << 12
+
+// Generates key pairs as per GeneratePrivatePublicKeyPair until one whose
+// compressed public key, as per MarshalCompressed, starts with prefix,
+// returning ErrVanityTimeout after maxVanityAttempts. The first byte of the
+// compressed form is 0x02 or 0x03, so a prefix starting with any other byte
+// never matches; each further byte makes a match 256 times less likely
+func GenerateKeyPairWithPrefix(curve elliptic.Curve, prefix []byte) (Key, error) {
+	for attempt := 0; attempt < maxVanityAttempts; attempt++ {
+		key, err := GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			return Key{}, err
+		}
+		if bytes.HasPrefix(MarshalCompressed(key), prefix) {
+			return key, nil
+		}
+		key.Zeroize()
+	}
+
+	return Key{}, fmt.Errorf("%w, %x after %d attempts", ErrVanityTimeout, prefix, maxVanityAttempts)
+}
@@ -0,0 +1,38 @@
+package ecdsaplay
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"testing"
+)
+
+func TestGenerateKeyPairWithPrefix(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), Secp256k1()} {
+		// The first byte of the compressed form is 0x02 or 0x03 with equal
+		// probability, so either hits within a few attempts
+		for _, prefix := range [][]byte{nil, {0x02}, {0x03}} {
+			key, err := GenerateKeyPairWithPrefix(curve, prefix)
+			if err != nil {
+				t.Fatalf("%s prefix %x: %v", curve.Params().Name, prefix, err)
+			}
+			if compressed := MarshalCompressed(key); !bytes.HasPrefix(compressed, prefix) {
+				t.Errorf("%s prefix %x: compressed public key %x", curve.Params().Name, prefix, compressed)
+			}
+			if err := key.Validate(); err != nil {
+				t.Errorf("%s prefix %x: %v", curve.Params().Name, prefix, err)
+			}
+		}
+	}
+
+	// No compressed public key starts with 0x04
+	if key, err := GenerateKeyPairWithPrefix(elliptic.P256(), []byte{0x04}); !errors.Is(err, ErrVanityTimeout) {
+		t.Errorf("prefix 04: GenerateKeyPairWithPrefix = (%v, %v), want %v", key, err, ErrVanityTimeout)
+	}
+
+	// Errors of key generation are returned as they are, e.g. for a bare
+	// *elliptic.CurveParams
+	if _, err := GenerateKeyPairWithPrefix(elliptic.P256().Params(), []byte{0x02}); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Errorf("bare CurveParams: err = %v, want %v", err, ErrUnsupportedCurve)
+	}
+}
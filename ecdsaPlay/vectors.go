@@ -0,0 +1,92 @@
+package ecdsaplay
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Known answer test vector, as stored in the JSON files of testdata/vectors,
+// for which SignWithK must reproduce (r, s) exactly
+type TestVector struct {
+	Source     string `json:"source"`
+	Curve      string `json:"curve"`
+	PrivateHex string `json:"privateHex"`
+	HashHex    string `json:"hashHex"`
+	KHex       string `json:"kHex"`
+	RHex       string `json:"rHex"`
+	SHex       string `json:"sHex"`
+}
+
+// Reads every vector of every *.json file in dir, in the order of the file
+// names; a directory without vector files is an error
+func ReadTestVectors(dir string) ([]TestVector, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("Error: No vector files in %s", dir)
+	}
+
+	var vectors []TestVector
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var fileVectors []TestVector
+		if err = json.Unmarshal(contents, &fileVectors); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if len(fileVectors) == 0 {
+			return nil, fmt.Errorf("Error: No vectors in %s", file)
+		}
+		vectors = append(vectors, fileVectors...)
+	}
+	return vectors, nil
+}
+
+// Whether SignWithK reproduces (r, s) of the vector and Verify accepts it
+func (vector TestVector) Check() (reproduced, verified bool, err error) {
+	curve, ok := curveByName(vector.Curve)
+	if !ok {
+		return false, false, fmt.Errorf("%w %q", ErrUnsupportedCurve, vector.Curve)
+	}
+
+	private, err := hexToInt(vector.PrivateHex)
+	if err != nil {
+		return false, false, err
+	}
+	k, err := hexToInt(vector.KHex)
+	if err != nil {
+		return false, false, err
+	}
+	r, err := hexToInt(vector.RHex)
+	if err != nil {
+		return false, false, err
+	}
+	s, err := hexToInt(vector.SHex)
+	if err != nil {
+		return false, false, err
+	}
+	messageHash, err := hex.DecodeString(vector.HashHex)
+	if err != nil {
+		return false, false, err
+	}
+
+	key, err := NewKeyFromScalar(curve, private)
+	if err != nil {
+		return false, false, err
+	}
+	signatureR, signatureS, err := SignWithK(key, messageHash, k)
+	if err != nil {
+		return false, false, err
+	}
+
+	reproduced = signatureR.Cmp(r) == 0 && signatureS.Cmp(s) == 0
+	verified = Verify(r, s, key.PublicX, key.PublicY, curve, messageHash)
+	return reproduced, verified, nil
+}
@@ -0,0 +1,51 @@
+package ecdsaplay
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTestVectors(t *testing.T) {
+	vectors, err := ReadTestVectors(filepath.Join("testdata", "vectors"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At least one vector per NIST curve
+	var curves = make(map[string]bool)
+	for _, vector := range vectors {
+		curves[vector.Curve] = true
+	}
+	for _, curve := range nistCurves {
+		if !curves[curve.Params().Name] {
+			t.Errorf("no vector for %s", curve.Params().Name)
+		}
+	}
+
+	for _, vector := range vectors {
+		reproduced, verified, err := vector.Check()
+		if err != nil {
+			t.Fatalf("%s: %v", vector.Source, err)
+		}
+		if !reproduced {
+			t.Errorf("%s: SignWithK did not reproduce (%s, %s)", vector.Source, vector.RHex, vector.SHex)
+		}
+		if !verified {
+			t.Errorf("%s: Verify rejected the vector", vector.Source)
+		}
+	}
+}
+
+func TestReadTestVectorsEmptyDir(t *testing.T) {
+	if _, err := ReadTestVectors(t.TempDir()); err == nil {
+		t.Error("ReadTestVectors accepted a directory without vector files")
+	}
+}
+
+func TestTestVectorCheckUnknownCurve(t *testing.T) {
+	var vector = TestVector{Curve: "P-192"}
+	if _, _, err := vector.Check(); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Errorf("Check = %v, want ErrUnsupportedCurve", err)
+	}
+}
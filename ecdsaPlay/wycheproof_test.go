@@ -0,0 +1,70 @@
+package ecdsaplay
+
+import (
+	"crypto"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test file of Project Wycheproof for ECDSA verification with DER encoded
+// signatures, as vendored under testdata/wycheproof
+type wycheproofSuite struct {
+	NumberOfTests int `json:"numberOfTests"`
+	TestGroups    []struct {
+		PublicKeyDer string `json:"publicKeyDer"`
+		Sha          string `json:"sha"`
+		Tests        []struct {
+			TcID    int      `json:"tcId"`
+			Comment string   `json:"comment"`
+			Flags   []string `json:"flags"`
+			Msg     string   `json:"msg"`
+			Sig     string   `json:"sig"`
+			Result  string   `json:"result"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+func TestWycheproofP256SHA256(t *testing.T) {
+	contents, err := os.ReadFile(filepath.Join("testdata", "wycheproof", "ecdsa_secp256r1_sha256_test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite wycheproofSuite
+	if err = json.Unmarshal(contents, &suite); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every case is expected to pass; "acceptable" ones, which may go either
+	// way, do not occur in this file
+	var total int
+	for _, group := range suite.TestGroups {
+		if group.Sha != "SHA-256" {
+			t.Fatalf("hash %s, want SHA-256", group.Sha)
+		}
+		pub, err := ParsePublicKeyDER(hexBytes(group.PublicKeyDer))
+		if err != nil {
+			t.Fatalf("ParsePublicKeyDER(%s): %v", group.PublicKeyDer, err)
+		}
+
+		for _, test := range group.Tests {
+			total++
+			var h = crypto.SHA256.New()
+			h.Write(hexBytes(test.Msg))
+			valid, _ := VerifyDER(hexBytes(test.Sig), pub, h.Sum(nil))
+
+			switch test.Result {
+			case "valid", "invalid":
+				if valid != (test.Result == "valid") {
+					t.Errorf("tcId %d (%s, %v): VerifyDER = %v, want %s", test.TcID, test.Comment, test.Flags, valid, test.Result)
+				}
+			default:
+				t.Errorf("tcId %d: unexpected result %q", test.TcID, test.Result)
+			}
+		}
+	}
+	if total != suite.NumberOfTests {
+		t.Errorf("ran %d cases, want numberOfTests = %d", total, suite.NumberOfTests)
+	}
+}
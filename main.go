@@ -1,13 +1,32 @@
 package main
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"playgroundgo/ecdsaplay"
+	"math/big"
+	"os"
+	"path/filepath"
+	"playgroundgo/ecdsaPlay"
+	"strings"
 )
 
+var vectorsDir = flag.String("vectors", filepath.Join("ecdsaPlay", "testdata", "vectors"), "directory of JSON known answer test vectors")
+var wycheproofPath = flag.String("wycheproof", filepath.Join("ecdsaPlay", "testdata", "wycheproof", "ecdsa_secp256r1_sha256_test.json"), "Wycheproof ECDSA verification test file")
+
 func main() {
+	flag.Parse()
+
 	// Positive Test Case
 	fmt.Println("Positive Test Case")
 	key, err := ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
@@ -32,4 +51,578 @@ func main() {
 	verification = ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, newMessageHash[:])
 	fmt.Println("Valid Signature: ", verification)
 
+	// Interoperability Test Cases
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		fmt.Println("Interoperability Test Case (ecdsaplay.Sign, ecdsa.Verify) on", curve.Params().Name)
+		key, err = ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			panic(err)
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: key.PublicX, Y: key.PublicY}
+
+		signatureR, signatureS, err = ecdsaplay.Sign(key, messageHash[:])
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("Valid Signature: ", ecdsa.Verify(publicKey, messageHash[:], signatureR, signatureS))
+
+		fmt.Println("Interoperability Test Case (ecdsa.Sign, ecdsaplay.Verify) on", curve.Params().Name)
+		standardKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+
+		signatureR, signatureS, err = ecdsa.Sign(rand.Reader, standardKey, messageHash[:])
+		if err != nil {
+			panic(err)
+		}
+		verification = ecdsaplay.Verify(signatureR, signatureS, standardKey.X, standardKey.Y, curve, messageHash[:])
+		fmt.Println("Valid Signature: ", verification)
+	}
+
+	// Variable Length Hash Test Cases
+	sha384Hash := sha512.Sum384([]byte("Take the red pill!"))
+	sha512Hash := sha512.Sum512([]byte("Take the red pill!"))
+	for _, testCase := range []struct {
+		curve       elliptic.Curve
+		hashName    string
+		messageHash []byte
+	}{
+		{elliptic.P384(), "SHA-384", sha384Hash[:]},
+		{elliptic.P521(), "SHA-512", sha512Hash[:]},
+	} {
+		fmt.Println("Variable Length Hash Test Case", testCase.hashName, "on", testCase.curve.Params().Name)
+		key, err = ecdsaplay.GeneratePrivatePublicKeyPair(testCase.curve)
+		if err != nil {
+			panic(err)
+		}
+
+		signatureR, signatureS, err = ecdsaplay.Sign(key, testCase.messageHash)
+		if err != nil {
+			panic(err)
+		}
+		verification = ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, testCase.messageHash)
+		fmt.Println("Valid Signature: ", verification)
+	}
+
+	// Round Trip Test Cases
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		fmt.Println("Round Trip Test Case (random keys and hashes) on", curve.Params().Name)
+		failures := 0
+		for i := 0; i < 100; i++ {
+			key, err = ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+			if err != nil {
+				panic(err)
+			}
+
+			randomHash := make([]byte, 1+i%64)
+			if _, err = rand.Read(randomHash); err != nil {
+				panic(err)
+			}
+
+			signatureR, signatureS, err = ecdsaplay.Sign(key, randomHash)
+			if err != nil {
+				panic(err)
+			}
+			if !ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, randomHash) {
+				failures++
+			}
+		}
+		fmt.Println("Failures: ", failures)
+	}
+
+	// Supported Curve Test Cases, key generation, signing and verification on
+	// every supported curve, then key generation on a nil curve
+	for _, curve := range ecdsaplay.SupportedCurves() {
+		fmt.Println("Supported Curve Test Case on", curve.Params().Name)
+		key, err = ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			panic(err)
+		}
+		signatureR, signatureS, err = ecdsaplay.Sign(key, messageHash[:])
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("Valid Signature: ", ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, messageHash[:]))
+	}
+
+	fmt.Println("Supported Curve Test Case (nil curve)")
+	_, err = ecdsaplay.GeneratePrivatePublicKeyPair(nil)
+	fmt.Println("Unsupported Curve: ", errors.Is(err, ecdsaplay.ErrUnsupportedCurve))
+
+	// JWS Test Case, the ES256 example of appendix A.3 of RFC 7515
+	fmt.Println("JWS Test Case (RFC 7515 A.3 ES256)")
+	jwsKey := ecdsaplay.PublicKey{
+		X:     base64URLInt("f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"),
+		Y:     base64URLInt("x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"),
+		Curve: elliptic.P256(),
+	}
+	jwsSigningInput := []byte("eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ")
+	jwsSignature := "DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+	fmt.Println("Valid Signature: ", ecdsaplay.VerifyJWS(jwsSignature, jwsKey, jwsSigningInput, crypto.SHA256))
+
+	// DER Test Cases, r = 1 and s = 2 minimally encoded, then with r padded by a
+	// superfluous leading zero byte
+	fmt.Println("DER Test Case (minimal encoding)")
+	minimalDER := []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}
+	_, _, err = ecdsaplay.DecodeSignatureDER(minimalDER)
+	fmt.Println("Strict Decoding Accepted: ", err == nil)
+	_, _, err = ecdsaplay.LenientDecodeSignatureDER(minimalDER)
+	fmt.Println("Lenient Decoding Accepted: ", err == nil)
+
+	fmt.Println("DER Test Case (non-canonical encoding)")
+	paddedDER := []byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x02}
+	_, _, err = ecdsaplay.DecodeSignatureDER(paddedDER)
+	fmt.Println("Strict Decoding Accepted: ", err == nil)
+	_, _, err = ecdsaplay.LenientDecodeSignatureDER(paddedDER)
+	fmt.Println("Lenient Decoding Accepted: ", err == nil)
+
+	fmt.Println("DER Test Case (trailing junk)")
+	junkDER := append(append([]byte{}, minimalDER...), 0xde, 0xad)
+	_, _, _, err = ecdsaplay.DecodeSignatureDERWithPolicy(junkDER, ecdsaplay.RejectTrailing)
+	fmt.Println("RejectTrailing Decoding Accepted: ", err == nil)
+	_, _, consumed, err := ecdsaplay.DecodeSignatureDERWithPolicy(junkDER, ecdsaplay.AllowTrailing)
+	fmt.Println("AllowTrailing Decoding Accepted: ", err == nil, "Bytes Consumed: ", consumed, "of", len(junkDER))
+
+	// Signature Format Test Cases, DER to fixed width and back, then fixed
+	// width to DER and back, for several signatures on every NIST curve
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		fmt.Println("Signature Format Test Case (DERToFixed, FixedToDER) on", curve.Params().Name)
+		key, err = ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			panic(err)
+		}
+		failures := 0
+		for i := 0; i < 5; i++ {
+			derSignature, err := ecdsaplay.SignAndEncode(key, messageHash[:])
+			if err != nil {
+				panic(err)
+			}
+			fixedSignature, err := ecdsaplay.DERToFixed(derSignature, curve)
+			if err != nil || len(fixedSignature) != ecdsaplay.SignatureByteLen(curve) {
+				failures++
+				continue
+			}
+			roundTrip, err := ecdsaplay.FixedToDER(fixedSignature, curve)
+			if err != nil || !bytes.Equal(roundTrip, derSignature) {
+				failures++
+			}
+			roundTrip, err = ecdsaplay.DERToFixed(roundTrip, curve)
+			if err != nil || !bytes.Equal(roundTrip, fixedSignature) {
+				failures++
+			}
+		}
+		fmt.Println("Failures: ", failures)
+	}
+
+	// Bitcoin Test Case, SignBitcoin output must be BIP 66 strict DER with
+	// s <= N/2
+	fmt.Println("Bitcoin Test Case (SignBitcoin) on secp256k1")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(ecdsaplay.Secp256k1())
+	if err != nil {
+		panic(err)
+	}
+	halfN := new(big.Int).Rsh(key.Curve.Params().N, 1)
+	failures := 0
+	for i := 0; i < 20; i++ {
+		bitcoinSignature, err := ecdsaplay.SignBitcoin(key, messageHash[:])
+		if err != nil {
+			panic(err)
+		}
+		_, bitcoinS, err := ecdsaplay.DecodeSignatureDER(bitcoinSignature)
+		if !ecdsaplay.IsBIP66Signature(bitcoinSignature) || err != nil || bitcoinS.Cmp(halfN) > 0 {
+			failures++
+		}
+	}
+	fmt.Println("Failures: ", failures)
+
+	// Curve Name Test Cases, a P-256 key then a key on custom parameters
+	fmt.Println("Curve Name Test Case (P-256)")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Curve Name: ", key.CurveName(), "Byte Size: ", key.ByteSize())
+
+	fmt.Println("Curve Name Test Case (custom CurveParams)")
+	customParams := *elliptic.P256().Params()
+	customParams.Name = "custom"
+	customParams.B = big.NewInt(7)
+	fmt.Println("Curve Name: ", ecdsaplay.Key{Curve: &customParams}.CurveName())
+
+	// Compressed Public Key Test Cases, a valid and an invalid signature, then a
+	// malformed encoding of the public key
+	fmt.Println("Compressed Public Key Test Case (valid signature)")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(ecdsaplay.Secp256k1())
+	if err != nil {
+		panic(err)
+	}
+	signatureR, signatureS, err = ecdsaplay.Sign(key, messageHash[:])
+	if err != nil {
+		panic(err)
+	}
+	compressedPub := ecdsaplay.MarshalCompressed(key)
+	verification, err = ecdsaplay.VerifyCompressed(signatureR, signatureS, compressedPub, key.Curve, messageHash[:])
+	fmt.Println("Valid Signature: ", verification, "Error: ", err)
+
+	fmt.Println("Compressed Public Key Test Case (Invalid Message Hash)")
+	verification, err = ecdsaplay.VerifyCompressed(signatureR, signatureS, compressedPub, key.Curve, newMessageHash[:])
+	fmt.Println("Valid Signature: ", verification, "Error: ", err)
+
+	fmt.Println("Compressed Public Key Test Case (invalid encoding)")
+	compressedPub[0] = 0x05
+	verification, err = ecdsaplay.VerifyCompressed(signatureR, signatureS, compressedPub, key.Curve, messageHash[:])
+	fmt.Println("Valid Signature: ", verification, "Error: ", err != nil)
+
+	// X-Only Public Key Test Cases, the key as x and the parity of y, then an
+	// x that is not the x-coordinate of any point
+	fmt.Println("X-Only Public Key Test Case (x and y parity)")
+	verification, err = ecdsaplay.VerifyXOnly(signatureR, signatureS, key.PublicX, int(key.PublicY.Bit(0)), key.Curve, messageHash[:])
+	fmt.Println("Valid Signature: ", verification, "Error: ", err)
+
+	// On secp256k1, x^3 + 7 for x = 5 is not a quadratic residue mod P
+	fmt.Println("X-Only Public Key Test Case (x without a square root)")
+	verification, err = ecdsaplay.VerifyXOnly(signatureR, signatureS, big.NewInt(5), 0, key.Curve, messageHash[:])
+	fmt.Println("Valid Signature: ", verification, "Error: ", err != nil)
+
+	// Oversized Hash Test Case, a 1 MB message passed in place of its hash
+	fmt.Println("Oversized Hash Test Case (1 MB) on P-256")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	_, _, err = ecdsaplay.Sign(key, make([]byte, 1<<20))
+	fmt.Println("Hash Too Long: ", errors.Is(err, ecdsaplay.ErrHashTooLong))
+
+	// String Test Case, the private scalar must appear in no format of the key
+	fmt.Println("String Test Case (masked private key)")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	leaked := false
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%d", "%x"} {
+		formatted := fmt.Sprintf(format, key)
+		if strings.Contains(formatted, key.Private.Text(16)) || strings.Contains(formatted, key.Private.Text(10)) {
+			leaked = true
+		}
+	}
+	fmt.Println("Key: ", key)
+	fmt.Println("Private Key Leaked: ", leaked)
+
+	// Public Key Serialization Test Cases, the uncompressed and compressed
+	// forms round-tripping, then an unknown prefix byte
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(ecdsaplay.Secp256k1())
+	if err != nil {
+		panic(err)
+	}
+	for _, form := range []struct {
+		name       string
+		compressed bool
+	}{
+		{"uncompressed", false},
+		{"compressed", true},
+	} {
+		fmt.Println("Public Key Serialization Test Case (" + form.name + ") on secp256k1")
+		serialized := ecdsaplay.SerializePublicKey(key.PublicKey(), form.compressed)
+		deserialized, err := ecdsaplay.DeserializePublicKey(key.Curve, serialized)
+		fmt.Println("Round Trip: ", err == nil && deserialized.X.Cmp(key.PublicX) == 0 && deserialized.Y.Cmp(key.PublicY) == 0, "Length: ", len(serialized))
+	}
+
+	fmt.Println("Public Key Serialization Test Case (prefix 05) on secp256k1")
+	badPrefix := ecdsaplay.SerializePublicKey(key.PublicKey(), true)
+	badPrefix[0] = 0x05
+	_, err = ecdsaplay.DeserializePublicKey(key.Curve, badPrefix)
+	fmt.Println("Rejected: ", err != nil)
+
+	// Debug Checks Test Case, signing and verification with the internal
+	// self-checks enabled, which panic should an inverse be wrong
+	fmt.Println("Debug Checks Test Case (DebugChecks enabled)")
+	ecdsaplay.DebugChecks = true
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	signatureR, signatureS, err = ecdsaplay.Sign(key, messageHash[:])
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Valid Signature: ", ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, messageHash[:]))
+	ecdsaplay.DebugChecks = false
+
+	// Entropy Consumption Test Case, a single k on P-256 takes the 256 bits of
+	// N plus 64 extra bits
+	fmt.Println("Entropy Consumption Test Case (GeneratePreMessageSecretFrom) on P-256")
+	countingReader := ecdsaplay.NewCountingReader(rand.Reader)
+	if _, err = ecdsaplay.GeneratePreMessageSecretFrom(countingReader, elliptic.P256()); err != nil {
+		panic(err)
+	}
+	fmt.Println("Bytes Consumed: ", countingReader.Count(), "Expected: ", (elliptic.P256().Params().N.BitLen()+64)/8)
+
+	// Private Bytes Test Case, a private scalar of a single byte
+	fmt.Println("Private Bytes Test Case (d = 1) on P-521")
+	key, err = ecdsaplay.NewKeyFromScalar(elliptic.P521(), big.NewInt(1))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Byte Length: ", len(key.PrivateBytes()), "Minimal Byte Length: ", len(key.Private.Bytes()))
+
+	// Valid Scalar Test Cases, the bounds of [1, N-1] and either side of them
+	n := elliptic.P256().Params().N
+	for _, scalar := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"0", big.NewInt(0)},
+		{"1", big.NewInt(1)},
+		{"N-1", new(big.Int).Sub(n, big.NewInt(1))},
+		{"N", new(big.Int).Set(n)},
+		{"N+1", new(big.Int).Add(n, big.NewInt(1))},
+		{"-1", big.NewInt(-1)},
+		{"-(N-1)", new(big.Int).Neg(new(big.Int).Sub(n, big.NewInt(1)))},
+	} {
+		fmt.Println("Valid Scalar Test Case (" + scalar.name + ") on P-256")
+		fmt.Println("Valid Scalar: ", ecdsaplay.IsValidScalar(scalar.value, elliptic.P256()))
+	}
+
+	// Recovery Test Case
+	fmt.Println("Recovery Test Case (SignRecoverable, RecoverPublicKey) on secp256k1")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(ecdsaplay.Secp256k1())
+	if err != nil {
+		panic(err)
+	}
+	failures = 0
+	for i := 0; i < 100; i++ {
+		randomHash := make([]byte, 32)
+		if _, err = rand.Read(randomHash); err != nil {
+			panic(err)
+		}
+
+		signatureR, signatureS, recid, err := ecdsaplay.SignRecoverable(key, randomHash)
+		if err != nil {
+			panic(err)
+		}
+		recoveredX, recoveredY, err := ecdsaplay.RecoverPublicKey(ecdsaplay.Signature{R: signatureR, S: signatureS}, recid, key.Curve, randomHash)
+		if err != nil || recoveredX.Cmp(key.PublicX) != 0 || recoveredY.Cmp(key.PublicY) != 0 {
+			failures++
+		}
+	}
+	fmt.Println("Failures: ", failures)
+
+	// Nonce Reuse Test Cases, two messages signed with the same k and then
+	// with distinct k
+	fmt.Println("Nonce Reuse Test Case (reused k)")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	reusedK := big.NewInt(123456789)
+	firstR, firstS, err := ecdsaplay.SignWithK(key, messageHash[:], reusedK)
+	if err != nil {
+		panic(err)
+	}
+	secondR, secondS, err := ecdsaplay.SignWithK(key, newMessageHash[:], reusedK)
+	if err != nil {
+		panic(err)
+	}
+	firstSignature := ecdsaplay.Signature{R: firstR, S: firstS}
+	secondSignature := ecdsaplay.Signature{R: secondR, S: secondS}
+	fmt.Println("Same Nonce: ", ecdsaplay.HaveSameNonce(firstSignature, secondSignature))
+	recoveredPrivate, err := ecdsaplay.RecoverPrivateFromReusedNonce(firstSignature, secondSignature, messageHash[:], newMessageHash[:], key.Curve)
+	fmt.Println("Private Key Recovered: ", err == nil && recoveredPrivate.Cmp(key.Private) == 0)
+
+	fmt.Println("Nonce Reuse Test Case (distinct k)")
+	secondR, secondS, err = ecdsaplay.SignWithK(key, newMessageHash[:], big.NewInt(987654321))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Same Nonce: ", ecdsaplay.HaveSameNonce(firstSignature, ecdsaplay.Signature{R: secondR, S: secondS}))
+
+	// Vanity Test Cases, a prefix matching half of all compressed public keys
+	// and one that no compressed public key can match
+	fmt.Println("Vanity Test Case (prefix 02)")
+	key, err = ecdsaplay.GenerateKeyPairWithPrefix(elliptic.P256(), []byte{0x02})
+	fmt.Println("Key Found: ", err == nil && ecdsaplay.MarshalCompressed(key)[0] == 0x02)
+
+	fmt.Println("Vanity Test Case (prefix 04)")
+	_, err = ecdsaplay.GenerateKeyPairWithPrefix(elliptic.P256(), []byte{0x04})
+	fmt.Println("Timed Out: ", errors.Is(err, ecdsaplay.ErrVanityTimeout))
+
+	// Prehashed Test Cases, z = SHA-256(domain || messageHash) for a custom
+	// domain tag, then verified under a different tag
+	fmt.Println("Prehashed Test Case (domain separated z)")
+	key, err = ecdsaplay.GeneratePrivatePublicKeyPair(elliptic.P256())
+	if err != nil {
+		panic(err)
+	}
+	domainZ := sha256.Sum256(append([]byte("ecdsaplay/example-domain"), messageHash[:]...))
+	signature, err := ecdsaplay.SignPrehashed(key, domainZ[:])
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Valid Signature: ", ecdsaplay.VerifyPrehashed(signature, key.PublicKey(), domainZ[:]))
+
+	fmt.Println("Prehashed Test Case (different domain tag)")
+	otherDomainZ := sha256.Sum256(append([]byte("ecdsaplay/other-domain"), messageHash[:]...))
+	fmt.Println("Valid Signature: ", ecdsaplay.VerifyPrehashed(signature, key.PublicKey(), otherDomainZ[:]))
+
+	// Hedged Deterministic Test Cases, RFC 6979 with identical and then with
+	// different extra entropy
+	fmt.Println("Hedged Deterministic Test Case (identical extra entropy)")
+	firstR, firstS, err = ecdsaplay.SignDeterministicWithEntropy(key, messageHash[:], []byte("extra entropy"))
+	if err != nil {
+		panic(err)
+	}
+	secondR, secondS, err = ecdsaplay.SignDeterministicWithEntropy(key, messageHash[:], []byte("extra entropy"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Identical Signatures: ", firstR.Cmp(secondR) == 0 && firstS.Cmp(secondS) == 0)
+
+	fmt.Println("Hedged Deterministic Test Case (different extra entropy)")
+	secondR, secondS, err = ecdsaplay.SignDeterministicWithEntropy(key, messageHash[:], []byte("other entropy"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Identical Signatures: ", firstR.Cmp(secondR) == 0 && firstS.Cmp(secondS) == 0)
+	fmt.Println("Valid Signature: ", ecdsaplay.Verify(secondR, secondS, key.PublicX, key.PublicY, key.Curve, messageHash[:]))
+
+	// Crosscheck Test Cases, VerifyCrosscheck must agree with Verify on valid
+	// signatures and on signatures over another message hash
+	for _, curve := range []elliptic.Curve{elliptic.P256(), ecdsaplay.Secp256k1()} {
+		fmt.Println("Crosscheck Test Case (VerifyCrosscheck, Verify) on", curve.Params().Name)
+		key, err = ecdsaplay.GeneratePrivatePublicKeyPair(curve)
+		if err != nil {
+			panic(err)
+		}
+		disagreements := 0
+		for i := 0; i < 10; i++ {
+			randomHash := make([]byte, 32)
+			if _, err = rand.Read(randomHash); err != nil {
+				panic(err)
+			}
+			signatureR, signatureS, err = ecdsaplay.Sign(key, randomHash)
+			if err != nil {
+				panic(err)
+			}
+
+			for _, hash := range [][]byte{randomHash, newMessageHash[:]} {
+				valid, err := ecdsaplay.VerifyCrosscheck(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, hash)
+				if errors.Is(err, ecdsaplay.ErrCrosscheckMismatch) || valid != ecdsaplay.Verify(signatureR, signatureS, key.PublicX, key.PublicY, key.Curve, hash) {
+					disagreements++
+				}
+			}
+		}
+		fmt.Println("Disagreements: ", disagreements)
+	}
+
+	// Known Answer Test Cases
+	runTestVectors(*vectorsDir)
+
+	// Wycheproof Test Cases
+	runWycheproof(*wycheproofPath)
+
+}
+
+// Unsigned big-endian integer encoded as BASE64URL without padding, as in JWK
+func base64URLInt(s string) *big.Int {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// Runs every vector of every *.json file in dir through SignWithK and Verify
+func runTestVectors(dir string) {
+	vectors, err := ecdsaplay.ReadTestVectors(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, vector := range vectors {
+		fmt.Println("Known Answer Test Case", vector.Source, "on", vector.Curve)
+		reproduced, verified, err := vector.Check()
+		if err != nil {
+			fmt.Println("Error: ", err)
+			continue
+		}
+		fmt.Println("Reproduced Signature: ", reproduced)
+		fmt.Println("Valid Signature: ", verified)
+	}
+}
+
+// Test file of Project Wycheproof (github.com/C2SP/wycheproof) for ECDSA
+// verification with DER encoded signatures, e.g. ecdsa_secp256r1_sha256_test.json
+type wycheproofSuite struct {
+	TestGroups []struct {
+		PublicKeyDer string `json:"publicKeyDer"`
+		Sha          string `json:"sha"`
+		Tests        []struct {
+			TcID    int    `json:"tcId"`
+			Comment string `json:"comment"`
+			Msg     string `json:"msg"`
+			Sig     string `json:"sig"`
+			Result  string `json:"result"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+// Runs every case of the Wycheproof file through VerifyDER and reports the
+// cases whose verdict differs from the expected result: "valid" signatures
+// must verify and "invalid" ones must be rejected, while "acceptable" ones
+// (e.g. BER rather than DER encodings) may go either way. The P-256 file is
+// vendored under ecdsaPlay/testdata/wycheproof; any other file is skipped
+// when missing
+func runWycheproof(file string) {
+	contents, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		fmt.Println("Wycheproof Test Cases skipped, no file at", file)
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	var suite wycheproofSuite
+	if err = json.Unmarshal(contents, &suite); err != nil {
+		panic(fmt.Sprint(file, ": ", err))
+	}
+
+	var hashes = map[string]crypto.Hash{"SHA-256": crypto.SHA256, "SHA-384": crypto.SHA384, "SHA-512": crypto.SHA512}
+
+	fmt.Println("Wycheproof Test Cases", filepath.Base(file))
+	total, failures := 0, 0
+	for _, group := range suite.TestGroups {
+		keyDer, err := hex.DecodeString(group.PublicKeyDer)
+		if err != nil {
+			panic(err)
+		}
+		pub, err := ecdsaplay.ParsePublicKeyDER(keyDer)
+		if err != nil {
+			fmt.Println("Error: ", err)
+			continue
+		}
+		hashFunc, ok := hashes[group.Sha]
+		if !ok {
+			fmt.Println("Error: unsupported hash", group.Sha)
+			continue
+		}
+
+		for _, test := range group.Tests {
+			msg, _ := hex.DecodeString(test.Msg)
+			sig, _ := hex.DecodeString(test.Sig)
+			h := hashFunc.New()
+			h.Write(msg)
+
+			valid, _ := ecdsaplay.VerifyDER(sig, pub, h.Sum(nil))
+
+			total++
+			if (test.Result == "valid" && !valid) || (test.Result == "invalid" && valid) {
+				failures++
+				fmt.Println("Failed Test Case", test.TcID, test.Comment, "expected", test.Result, "got", valid)
+			}
+		}
+	}
+	fmt.Println("Failures: ", failures, "of", total)
 }